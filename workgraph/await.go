@@ -0,0 +1,245 @@
+package workgraph
+
+import (
+	"errors"
+	"reflect"
+)
+
+// AwaitAll awaits each of the given promises in turn using the given worker,
+// returning all of their values if they all succeed.
+//
+// The returned slice always has the same length as the given slice of
+// promises, and the value at each index is the result of the promise at the
+// same index, regardless of the order in which the requests were resolved.
+//
+// If any of the promises resolves with an error then AwaitAll returns
+// immediately with that error, without awaiting any subsequent promises. In
+// that case the returned slice contains only the values of the promises
+// that were awaited before the failing one, with zero values for the rest.
+//
+// Because a worker can await only one promise at a time, each promise is
+// checked for self-dependency separately as it's awaited. If awaiting any one
+// of the promises would create a self-dependency cycle then the whole call
+// fails with [ErrSelfDependency], even if the other promises could have been
+// resolved.
+func AwaitAll[T any](w *Worker, promises []Promise[T]) ([]T, error) {
+	ret := make([]T, len(promises))
+	for i, promise := range promises {
+		v, err := promise.Await(w)
+		if err != nil {
+			return ret, err
+		}
+		ret[i] = v
+	}
+	return ret, nil
+}
+
+// Zip awaits the two given promises in turn using the given worker,
+// returning both of their values if they both succeed.
+//
+// This is like [AwaitAll] for two promises of different result types. If a
+// fails then Zip returns its error immediately without awaiting b, and
+// otherwise it returns the error from b, if any. In either case the value
+// of the failing promise and any that follow it are zero.
+func Zip[A, B any](w *Worker, a Promise[A], b Promise[B]) (A, B, error) {
+	var zeroA A
+	var zeroB B
+	av, err := a.Await(w)
+	if err != nil {
+		return zeroA, zeroB, err
+	}
+	bv, err := b.Await(w)
+	if err != nil {
+		return av, zeroB, err
+	}
+	return av, bv, nil
+}
+
+// AwaitAllSettled awaits each of the given promises in turn using the given
+// worker, returning the outcome of each one.
+//
+// Unlike [AwaitAll], this awaits all of the promises even if some of them
+// fail, so that the caller can see all of the errors. The returned slice
+// always has the same length as the given slice of promises, and the outcome
+// at each index belongs to the promise at the same index.
+func AwaitAllSettled[T any](w *Worker, promises []Promise[T]) []Settled[T] {
+	ret := make([]Settled[T], len(promises))
+	for i, promise := range promises {
+		ret[i].Value, ret[i].Err = promise.Await(w)
+	}
+	return ret
+}
+
+// CollectErrors is like [AwaitAll] except that it awaits all of the promises
+// even if some of them fail, and then returns all of their errors together
+// using [errors.Join].
+//
+// The value at each index of the returned slice is the value of the
+// promise at the same index, or the zero value of T if that promise failed.
+// The errors are joined in the same order as the promises, and usage faults
+// such as [ErrSelfDependency] are included just like any other error, so
+// they can be detected using [errors.As].
+func CollectErrors[T any](w *Worker, promises []Promise[T]) ([]T, error) {
+	ret := make([]T, len(promises))
+	var errs []error
+	for i, promise := range promises {
+		v, err := promise.Await(w)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ret[i] = v
+	}
+	return ret, errors.Join(errs...)
+}
+
+// AwaitAny blocks until at least one of the given promises is resolved and
+// then returns the index of that promise along with its result.
+//
+// If more than one of the promises is already resolved when AwaitAny is
+// called then the one with the lowest index is returned.
+//
+// A worker can be awaiting only one request at a time, so AwaitAny does not
+// register its worker as awaiting any of the given promises. Instead, before
+// blocking it excludes any promise that the worker could not await without
+// creating a self-dependency, since such a promise can never be resolved
+// while the worker is waiting. If that excludes all of the promises then
+// AwaitAny instead awaits the first promise in the usual way, which then
+// fails with [ErrSelfDependency].
+//
+// Because the worker is not registered as awaiting anything while blocked,
+// a cycle that only forms after AwaitAny begins blocking, through another
+// worker that awaits a request this worker is responsible for, cannot be
+// detected and will deadlock. Use AwaitAny only when the given promises
+// cannot depend on anything the calling worker is responsible for.
+//
+// As with [Promise.Await], the worker may be nil to represent a "root"
+// waiter that isn't responsible for any requests. If the worker was created
+// by [NewWorkerContext] then AwaitAny returns early with the context's error
+// if that context is cancelled before any of the promises are resolved, in
+// which case the returned index is -1.
+//
+// AwaitAny panics if given no promises at all.
+func AwaitAny[T any](w *Worker, promises []Promise[T]) (index int, value T, err error) {
+	if len(promises) == 0 {
+		panic("AwaitAny with no promises")
+	}
+	if w == nil {
+		// A root waiter behaves just like a new worker that isn't
+		// responsible for anything, which can therefore never be part of
+		// a self-dependency cycle, and which can run any lazy request.
+		w = NewWorker()
+	}
+	if waitingFor := w.inner.awaiting.Load(); waitingFor != nil {
+		panic(w.inner.errConcurrentAwait(nil))
+	}
+	for i, promise := range promises {
+		if result := promise.inner.result.Load(); result != nil {
+			value, err := resultRet[T](result)
+			return i, value, err
+		}
+	}
+	ctx := w.Context()
+	if err := ctx.Err(); err != nil {
+		return -1, value, err
+	}
+
+	cases := make([]reflect.SelectCase, 0, len(promises)+1)
+	indices := make([]int, 0, len(promises))
+	for i, promise := range promises {
+		if selfDependency, _ := detectSelfDependency(promise.inner, w.inner, false); selfDependency {
+			continue
+		}
+		if run := promise.inner.claimLazy(w); run != nil {
+			// A lazy request would never be resolved unless someone awaits
+			// it, so we'll run it now and then return its result.
+			run(w)
+			value, err := promise.Await(w)
+			return i, value, err
+		}
+		cases = append(cases, reflect.SelectCase{
+			Dir:  reflect.SelectRecv,
+			Chan: reflect.ValueOf(promise.inner.doneChan()),
+		})
+		indices = append(indices, i)
+	}
+	if len(cases) == 0 {
+		value, err := promises[0].Await(w)
+		return 0, value, err
+	}
+
+	cases = append(cases, reflect.SelectCase{
+		Dir:  reflect.SelectRecv,
+		Chan: reflect.ValueOf(ctx.Done()),
+	})
+	chosen, _, _ := reflect.Select(cases)
+	if chosen == len(indices) {
+		// If one of the promises was resolved at the same time as the
+		// context was cancelled then we prefer to return its result.
+		for _, i := range indices {
+			if result := promises[i].inner.result.Load(); result != nil {
+				value, err := resultRet[T](result)
+				return i, value, err
+			}
+		}
+		return -1, value, ctx.Err()
+	}
+	i := indices[chosen]
+	value, err = resultRet[T](promises[i].inner.result.Load())
+	return i, value, err
+}
+
+// AwaitWithFallback awaits the primary promise using the given worker and
+// returns its result if it succeeds. Otherwise, it awaits the fallback
+// promise and returns its result instead.
+//
+// The fallback is used for any error from the primary promise, including
+// usage faults such as [ErrSelfDependency] and [ErrUnresolved]. The two
+// promises are always awaited in sequence, and so if the fallback promise
+// also cannot be resolved without a self-dependency then its own
+// [ErrSelfDependency] error is returned.
+func AwaitWithFallback[T any](w *Worker, primary, fallback Promise[T]) (T, error) {
+	v, err := primary.Await(w)
+	if err == nil {
+		return v, nil
+	}
+	return fallback.Await(w)
+}
+
+// GatherMap awaits each of the promises in the given map using the given
+// worker, returning a map of their values with the same keys if they all
+// succeed.
+//
+// If any of the promises fails then GatherMap returns immediately with an
+// [ErrKey] describing which key's promise failed, without waiting for any
+// promises that it hadn't yet awaited. The promises are awaited in an
+// unspecified order, and so if more than one would fail then which one is
+// reported is also unspecified.
+func GatherMap[K comparable, V any](w *Worker, m map[K]Promise[V]) (map[K]V, error) {
+	ret := make(map[K]V, len(m))
+	for k, promise := range m {
+		v, err := promise.Await(w)
+		if err != nil {
+			return nil, ErrKey[K]{Key: k, Err: err}
+		}
+		ret[k] = v
+	}
+	return ret, nil
+}
+
+// AsError awaits the given promise using the given worker and returns only
+// its error, discarding its value.
+//
+// This is for situations where a worker depends on a request only for
+// sequencing or for its side-effects, and so has no use for its value.
+func AsError[T any](w *Worker, p Promise[T]) error {
+	_, err := p.Await(w)
+	return err
+}
+
+// Settled describes the outcome of a request, for functions that collect
+// the outcomes of many requests without discarding either part.
+type Settled[T any] struct {
+	Value T
+	Err   error
+}
@@ -0,0 +1,327 @@
+package workgraph_test
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apparentlymart/go-workgraph/workgraph"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestAwaitAll(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolvers := make([]workgraph.Resolver[int], 4)
+	promises := make([]workgraph.Promise[int], 4)
+	for i := range resolvers {
+		resolvers[i], promises[i] = workgraph.NewRequest[int](mainWorker)
+	}
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		// We resolve the requests in reverse order, but the results
+		// should still be returned in the order of the promises.
+		for i := len(resolvers) - 1; i >= 0; i-- {
+			resolvers[i].ReportSuccess(w, i*10)
+		}
+	}, resolversContainer[int](resolvers))
+
+	got, err := workgraph.AwaitAll(mainWorker, promises)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []int{0, 10, 20, 30}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("wrong results\n" + diff)
+	}
+}
+
+// resolversContainer is a test helper for delegating a whole slice of
+// resolvers to a new worker at once.
+type resolversContainer[T any] []workgraph.Resolver[T]
+
+func (c resolversContainer[T]) ContainedResolvers() iter.Seq[workgraph.AnyResolver] {
+	return func(yield func(workgraph.AnyResolver) bool) {
+		for _, r := range c {
+			if !yield(r) {
+				return
+			}
+		}
+	}
+}
+
+func TestAwaitWithFallback(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	primaryResolver, primary := workgraph.NewRequest[string](mainWorker)
+	fallbackResolver, fallback := workgraph.NewRequest[string](mainWorker)
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		primaryResolver.ReportError(w, errors.New("primary failed"))
+		fallbackResolver.ReportSuccess(w, "fallback")
+	}, primaryResolver, fallbackResolver)
+
+	got, err := workgraph.AwaitWithFallback(mainWorker, primary, fallback)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "fallback"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestAwaitAll_selfDependency(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	otherResolver, otherPromise := workgraph.NewRequest[int](mainWorker)
+	selfResolver, selfPromise := workgraph.NewRequest[int](mainWorker)
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		otherResolver.ReportSuccess(w, 1)
+	}, otherResolver)
+
+	// mainWorker is still responsible for selfPromise, so awaiting it is
+	// a self-dependency even though the other promise resolves fine.
+	_, err := workgraph.AwaitAll(mainWorker, []workgraph.Promise[int]{otherPromise, selfPromise})
+	selfDepErr, ok := err.(workgraph.ErrSelfDependency)
+	if !ok {
+		t.Fatalf("wrong error %v; want %T", err, selfDepErr)
+	}
+	wantIDs := []workgraph.RequestID{selfResolver.RequestID()}
+	if diff := cmp.Diff(wantIDs, selfDepErr.RequestIDs); diff != "" {
+		t.Error("wrong request ids\n" + diff)
+	}
+}
+
+func TestAwaitAllSettled(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolvers := make([]workgraph.Resolver[int], 3)
+	promises := make([]workgraph.Promise[int], 3)
+	for i := range resolvers {
+		resolvers[i], promises[i] = workgraph.NewRequest[int](mainWorker)
+	}
+	errFailed := errors.New("failed")
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		resolvers[2].ReportSuccess(w, 2)
+		resolvers[1].ReportError(w, errFailed)
+		resolvers[0].ReportSuccess(w, 0)
+	}, resolversContainer[int](resolvers))
+
+	got := workgraph.AwaitAllSettled(mainWorker, promises)
+	want := []workgraph.Settled[int]{
+		{Value: 0},
+		{Err: errFailed},
+		{Value: 2},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.EquateErrors()); diff != "" {
+		t.Error("wrong results\n" + diff)
+	}
+}
+
+func TestAwaitAny(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	// neverWorker must remain live until the end of the test, or else
+	// the garbage collector could resolve neverPromise with ErrUnresolved.
+	neverWorker := workgraph.NewWorker()
+	defer runtime.KeepAlive(neverWorker)
+	_, neverPromise := workgraph.NewRequest[string](neverWorker)
+	fastResolver, fastPromise := workgraph.NewRequest[string](mainWorker)
+	_, selfPromise := workgraph.NewRequest[string](mainWorker)
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		fastResolver.ReportSuccess(w, "fast")
+	}, fastResolver)
+
+	// selfPromise is mainWorker's own responsibility, so it must be
+	// skipped rather than causing a self-dependency error.
+	promises := []workgraph.Promise[string]{neverPromise, selfPromise, fastPromise}
+	i, got, err := workgraph.AwaitAny(mainWorker, promises)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if i != 2 {
+		t.Errorf("wrong index %d; want 2", i)
+	}
+	if want := "fast"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+	if selfPromise.IsResolved() {
+		t.Errorf("self-dependent promise was resolved")
+	}
+}
+
+func TestAwaitAny_nilWorker(t *testing.T) {
+	otherWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](otherWorker)
+	resolver.ReportSuccess(otherWorker, "Hello")
+
+	i, got, err := workgraph.AwaitAny(nil, []workgraph.Promise[string]{promise})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if i != 0 || got != "Hello" {
+		t.Errorf("wrong result (%d, %q); want (0, %q)", i, got, "Hello")
+	}
+}
+
+func TestAwaitAny_contextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	mainWorker := workgraph.NewWorkerContext(ctx)
+	otherWorker := workgraph.NewWorker()
+	defer runtime.KeepAlive(otherWorker)
+	_, promise1 := workgraph.NewRequest[string](otherWorker)
+	_, promise2 := workgraph.NewRequest[string](otherWorker)
+
+	// Neither promise is ever resolved, so AwaitAny can only return
+	// because the worker's context was cancelled.
+	time.AfterFunc(10*time.Millisecond, cancel)
+	i, _, err := workgraph.AwaitAny(mainWorker, []workgraph.Promise[string]{promise1, promise2})
+	if err != context.Canceled {
+		t.Errorf("wrong error %v; want %v", err, context.Canceled)
+	}
+	if i != -1 {
+		t.Errorf("wrong index %d; want -1", i)
+	}
+}
+
+func TestAwaitAny_allSelfDependent(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	_, promise1 := workgraph.NewRequest[string](mainWorker)
+	_, promise2 := workgraph.NewRequest[string](mainWorker)
+
+	i, _, err := workgraph.AwaitAny(mainWorker, []workgraph.Promise[string]{promise1, promise2})
+	if _, ok := err.(workgraph.ErrSelfDependency); !ok {
+		t.Fatalf("wrong error %v; want %T", err, workgraph.ErrSelfDependency{})
+	}
+	if i != 0 {
+		t.Errorf("wrong index %d; want 0", i)
+	}
+}
+
+func TestZip(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolverA, promiseA := workgraph.NewRequest[int](mainWorker)
+	resolverB, promiseB := workgraph.NewRequest[string](mainWorker)
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		resolverB.ReportSuccess(w, "b")
+		resolverA.ReportSuccess(w, 1)
+	}, resolverA, resolverB)
+
+	gotA, gotB, err := workgraph.Zip(mainWorker, promiseA, promiseB)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if gotA != 1 || gotB != "b" {
+		t.Errorf("wrong results %#v, %#v; want 1, \"b\"", gotA, gotB)
+	}
+}
+
+func TestZip_error(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolverA, promiseA := workgraph.NewRequest[int](mainWorker)
+	_, selfPromise := workgraph.NewRequest[string](mainWorker)
+	errFailed := errors.New("failed")
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		resolverA.Report(w, 2, errFailed)
+	}, resolverA)
+
+	// The second promise would be a self-dependency if awaited, so getting
+	// errFailed shows that Zip returned without awaiting it.
+	gotA, _, err := workgraph.Zip(mainWorker, promiseA, selfPromise)
+	if err != errFailed {
+		t.Errorf("wrong error %v; want %v", err, errFailed)
+	}
+	// The value reported along with the error is discarded.
+	if gotA != 0 {
+		t.Errorf("wrong value for failing promise %d; want 0", gotA)
+	}
+}
+
+func TestAsError(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	okResolver, okPromise := workgraph.NewRequest[int](mainWorker)
+	failResolver, failPromise := workgraph.NewRequest[int](mainWorker)
+	okResolver.ReportSuccess(mainWorker, 1)
+	failResolver.Report(mainWorker, 2, errors.New("oh no"))
+
+	if err := workgraph.AsError(mainWorker, okPromise); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := workgraph.AsError(mainWorker, failPromise); err == nil || err.Error() != "oh no" {
+		t.Errorf("wrong error %v; want oh no", err)
+	}
+}
+
+func TestGatherMap(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	promises := make(map[string]workgraph.Promise[int])
+	for i, k := range []string{"a", "b", "c"} {
+		resolver, promise := workgraph.NewRequest[int](mainWorker)
+		resolver.ReportSuccess(mainWorker, i)
+		promises[k] = promise
+	}
+
+	got, err := workgraph.GatherMap(mainWorker, promises)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := map[string]int{"a": 0, "b": 1, "c": 2}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("wrong results\n" + diff)
+	}
+}
+
+func TestGatherMap_error(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	promises := make(map[string]workgraph.Promise[int])
+	for _, k := range []string{"a", "b"} {
+		resolver, promise := workgraph.NewRequest[int](mainWorker)
+		resolver.ReportSuccess(mainWorker, 1)
+		promises[k] = promise
+	}
+	resolver, promise := workgraph.NewRequest[int](mainWorker)
+	resolver.ReportError(mainWorker, errors.New("oh no"))
+	promises["bad"] = promise
+
+	_, err := workgraph.GatherMap(mainWorker, promises)
+	var keyErr workgraph.ErrKey[string]
+	if !errors.As(err, &keyErr) {
+		t.Fatalf("wrong error %v; want %T", err, keyErr)
+	}
+	if got, want := keyErr.Key, "bad"; got != want {
+		t.Errorf("wrong key %q; want %q", got, want)
+	}
+	if got, want := err.Error(), "bad: oh no"; got != want {
+		t.Errorf("wrong message %q; want %q", got, want)
+	}
+}
+
+func TestCollectErrors(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolvers := make([]workgraph.Resolver[int], 3)
+	promises := make([]workgraph.Promise[int], 4)
+	for i := range resolvers {
+		resolvers[i], promises[i] = workgraph.NewRequest[int](mainWorker)
+	}
+	resolvers[0].ReportSuccess(mainWorker, 10)
+	resolvers[1].Report(mainWorker, 20, errors.New("oh no"))
+	resolvers[2].ReportSuccess(mainWorker, 30)
+
+	// The final promise depends on itself, so its self-dependency error is
+	// collected along with the ordinary error.
+	_, selfPromise := workgraph.NewRequest[int](mainWorker)
+	promises[3] = selfPromise
+
+	got, err := workgraph.CollectErrors(mainWorker, promises)
+	if diff := cmp.Diff([]int{10, 0, 30, 0}, got); diff != "" {
+		t.Error("wrong results\n" + diff)
+	}
+	if err == nil {
+		t.Fatal("unexpected success; want errors")
+	}
+	if !strings.Contains(err.Error(), "oh no") {
+		t.Errorf("joined error does not include the first failure: %s", err)
+	}
+	var selfDepErr workgraph.ErrSelfDependency
+	if !errors.As(err, &selfDepErr) {
+		t.Errorf("joined error does not include %T: %s", selfDepErr, err)
+	}
+}
@@ -0,0 +1,69 @@
+package workgraph
+
+// MapPromise returns a new promise whose result is the result of applying f
+// to the value of the given source promise, once it's resolved.
+//
+// If the source promise fails then the new promise fails with the same
+// error, without calling f. Otherwise the new promise resolves with
+// whatever f returns.
+//
+// The source promise is awaited by a new internal worker that's responsible
+// for the new request, and so awaiting the new promise is subject to the
+// usual self-dependency checks: if the worker awaiting the new promise is
+// responsible for the source request, the await fails with
+// [ErrSelfDependency].
+func MapPromise[A, B any](src Promise[A], f func(A) (B, error)) Promise[B] {
+	return spawn(nil, func(w *Worker) (B, error) {
+		a, err := src.Await(w)
+		if err != nil {
+			var zero B
+			return zero, err
+		}
+		return f(a)
+	})
+}
+
+// Then returns a new promise whose result is the result of calling f with
+// the value of the given source promise, once it's resolved.
+//
+// This is like [MapPromise] except that f receives the worker that's
+// responsible for the new request, and so f can itself await other promises
+// or create new requests. The worker is a child of the given worker, as
+// with [Spawn], and so the given worker must remain live until f has
+// returned.
+//
+// If the source promise fails then the new promise fails with the same
+// error, without calling f. If f directly or indirectly awaits a request
+// that depends on the new promise, such as by awaiting the source request
+// when the new promise's own result is needed to produce it, that await
+// fails with [ErrSelfDependency].
+func Then[A, B any](w *Worker, src Promise[A], f func(*Worker, A) (B, error)) Promise[B] {
+	return Spawn(w, func(w *Worker) (B, error) {
+		a, err := src.Await(w)
+		if err != nil {
+			var zero B
+			return zero, err
+		}
+		return f(w, a)
+	})
+}
+
+// CollectPromises returns a new promise that resolves with the values of all
+// of the given promises, in the same order as the promises.
+//
+// This is like [AwaitAll] except that it returns immediately, and the
+// promises are awaited by a new worker that's a child of the given worker,
+// as with [Spawn]. The given worker must therefore remain live until the new
+// promise is resolved. If any of the promises fails then the new promise
+// fails with the first error encountered, in the order of the promises.
+func CollectPromises[T any](w *Worker, promises []Promise[T]) Promise[[]T] {
+	// We take a copy so that the caller may reuse its slice.
+	promises = append([]Promise[T](nil), promises...)
+	return Spawn(w, func(w *Worker) ([]T, error) {
+		ret, err := AwaitAll(w, promises)
+		if err != nil {
+			return nil, err
+		}
+		return ret, nil
+	})
+}
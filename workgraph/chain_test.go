@@ -0,0 +1,134 @@
+package workgraph_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/apparentlymart/go-workgraph/workgraph"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMapPromise(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolver, src := workgraph.NewRequest[int](mainWorker)
+	mapped := workgraph.MapPromise(src, func(v int) (string, error) {
+		return strconv.Itoa(v), nil
+	})
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		resolver.ReportSuccess(w, 5)
+	}, resolver)
+
+	got, err := mapped.Await(mainWorker)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "5"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestMapPromise_error(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolver, src := workgraph.NewRequest[int](mainWorker)
+	called := false
+	mapped := workgraph.MapPromise(src, func(v int) (string, error) {
+		called = true
+		return strconv.Itoa(v), nil
+	})
+	errFailed := errors.New("failed")
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		resolver.ReportError(w, errFailed)
+	}, resolver)
+
+	_, err := mapped.Await(mainWorker)
+	if err != errFailed {
+		t.Errorf("wrong error %v; want %v", err, errFailed)
+	}
+	if called {
+		t.Error("mapping function was called for a failed promise")
+	}
+}
+
+func TestMapPromise_selfDependency(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	_, src := workgraph.NewRequest[int](mainWorker)
+	mapped := workgraph.MapPromise(src, func(v int) (string, error) {
+		return strconv.Itoa(v), nil
+	})
+
+	// mainWorker is responsible for src, so it can't await a promise that
+	// depends on src.
+	_, err := mapped.Await(mainWorker)
+	if _, ok := err.(workgraph.ErrSelfDependency); !ok {
+		t.Errorf("wrong error %v; want %T", err, workgraph.ErrSelfDependency{})
+	}
+}
+
+func TestThen(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolver, src := workgraph.NewRequest[int](mainWorker)
+	next := workgraph.Then(mainWorker, src, func(w *workgraph.Worker, v int) (string, error) {
+		inner := workgraph.Spawn(w, func(w *workgraph.Worker) (int, error) {
+			return v * 2, nil
+		})
+		doubled, err := inner.Await(w)
+		if err != nil {
+			return "", err
+		}
+		return strconv.Itoa(doubled), nil
+	})
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		resolver.ReportSuccess(w, 21)
+	}, resolver)
+
+	got, err := next.Await(mainWorker)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "42"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestThen_selfDependency(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolver, src := workgraph.NewRequest[int](mainWorker)
+	self := make(chan workgraph.Promise[string], 1)
+	next := workgraph.Then(mainWorker, src, func(w *workgraph.Worker, v int) (string, error) {
+		return (<-self).Await(w)
+	})
+	self <- next
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		resolver.ReportSuccess(w, 1)
+	}, resolver)
+
+	_, err := next.Await(mainWorker)
+	if _, ok := err.(workgraph.ErrSelfDependency); !ok {
+		t.Errorf("wrong error %v; want %T", err, workgraph.ErrSelfDependency{})
+	}
+}
+
+func TestCollectPromises(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolvers := make([]workgraph.Resolver[int], 3)
+	promises := make([]workgraph.Promise[int], 3)
+	for i := range resolvers {
+		resolvers[i], promises[i] = workgraph.NewRequest[int](mainWorker)
+	}
+	collected := workgraph.CollectPromises(mainWorker, promises)
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		for i := len(resolvers) - 1; i >= 0; i-- {
+			resolvers[i].ReportSuccess(w, i+1)
+		}
+	}, resolversContainer[int](resolvers))
+
+	got, err := collected.Await(mainWorker)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []int{1, 2, 3}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("wrong results\n" + diff)
+	}
+}
@@ -1,5 +1,12 @@
 package workgraph
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
 // ErrUnresolved is returned by [Promise.Await] if the [Worker]
 // responsible for resolving the request is garbage-collected before the
 // request is resolved.
@@ -17,12 +24,40 @@ type ErrUnresolved struct {
 	// RequestID is the request that was unresolved. This is always the ID of
 	// the request whose [Promise] the Await method was called on.
 	RequestID RequestID
+
+	// Cause is an optional error describing why the worker was dropped,
+	// such as the error passed to [Worker.Cancel]. This is nil if the worker
+	// was dropped without any specific cause, such as when it was garbage
+	// collected.
+	Cause error
 }
 
 func (err ErrUnresolved) Error() string {
+	if err.Cause != nil {
+		return "responsible worker was dropped before request was resolved: " + err.Cause.Error()
+	}
 	return "responsible worker was dropped before request was resolved"
 }
 
+// Unwrap returns the cause of the error, if any.
+func (err ErrUnresolved) Unwrap() error {
+	return err.Cause
+}
+
+// Is returns true if target is any [ErrUnresolved] value, regardless of its
+// fields, so that errors.Is(err, ErrUnresolved{}) can detect this category of
+// error.
+func (err ErrUnresolved) Is(target error) bool {
+	_, ok := target.(ErrUnresolved)
+	return ok
+}
+
+// Retryable returns true, because a request whose responsible worker was
+// dropped might succeed if requested again with a different worker.
+func (err ErrUnresolved) Retryable() bool {
+	return true
+}
+
 // ErrSelfDependency is returned by [Promise.Await] if a direct or
 // indirect self-dependency is created in the worker-and-request graph by
 // this or some other call to [Promise.Await].
@@ -36,8 +71,271 @@ type ErrSelfDependency struct {
 	// that describes the set of requested operations that together caused the
 	// problem.
 	RequestIDs []RequestID
+
+	// CyclePath lists the requests in the dependency cycle in the order
+	// that each depends on the next, starting with the request whose await
+	// completed the cycle. The first request is repeated at the end of the
+	// path so that each element is followed by the request that it waits
+	// for, via the worker responsible for it.
+	CyclePath []RequestID
 }
 
 func (err ErrSelfDependency) Error() string {
-	return "self-dependency detected"
+	// If any of the requests have labels then we'll list all of them, using
+	// the opaque string representation for any that don't.
+	labelled := false
+	names := make([]string, len(err.RequestIDs))
+	for i, id := range err.RequestIDs {
+		names[i] = id.Label()
+		if names[i] != "" {
+			labelled = true
+		} else {
+			names[i] = id.String()
+		}
+	}
+	if !labelled {
+		return "self-dependency detected"
+	}
+	return "self-dependency detected between " + strings.Join(names, ", ")
+}
+
+// Is returns true if target is any [ErrSelfDependency] value, regardless of
+// its fields, so that errors.Is(err, ErrSelfDependency{}) can detect this
+// category of error.
+func (err ErrSelfDependency) Is(target error) bool {
+	_, ok := target.(ErrSelfDependency)
+	return ok
+}
+
+// Retryable returns false, because retrying work that depends on itself would
+// just encounter the same self-dependency again.
+func (err ErrSelfDependency) Retryable() bool {
+	return false
+}
+
+// IsRetryable returns true if the given error represents a failure that
+// might not occur if the same work were attempted again.
+//
+// An error can decide its own retryability by implementing a method
+// "Retryable() bool". IsRetryable uses the result of that method for the
+// first error in the tree of wrapped errors that has it, in the same order
+// as [errors.As]. Errors that don't have such a method anywhere in their
+// tree are assumed to be retryable. A nil error is never retryable, because
+// there's nothing to retry.
+//
+// Helpers in this package that retry failed work use this function to decide
+// whether to try again.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var retryable interface{ Retryable() bool }
+	if errors.As(err, &retryable) {
+		return retryable.Retryable()
+	}
+	return true
+}
+
+// ErrNotStarted is returned when awaiting the promise returned by
+// [Once.Promise] if [Once.Do] had not yet been called when that promise
+// was obtained.
+type ErrNotStarted struct{}
+
+func (err ErrNotStarted) Error() string {
+	return "result requested before its computation was started"
+}
+
+// ErrNoQuorum is returned when awaiting the promise returned by [Quorum] if
+// the results of its producers could not satisfy its policy.
+type ErrNoQuorum struct{}
+
+func (err ErrNoQuorum) Error() string {
+	return "producers did not reach a quorum"
+}
+
+// ErrPanic is returned when awaiting a request whose result was being
+// produced by a function that panicked, in situations where this library
+// is responsible for calling that function, such as in [Spawn] and [Once.Do].
+type ErrPanic struct {
+	// Value is the value that was passed to panic.
+	Value any
+
+	// Stack is the stack trace of the goroutine that panicked, as returned
+	// by [runtime/debug.Stack] while recovering from the panic.
+	Stack []byte
+}
+
+func (err ErrPanic) Error() string {
+	return fmt.Sprintf("panic: %v", err.Value)
+}
+
+// ErrStillResponsible is returned by [Worker.Reset] if the worker is still
+// responsible for resolving some requests.
+type ErrStillResponsible struct {
+	// RequestIDs are the identifiers of the unresolved requests that the
+	// worker is still responsible for, in no particular order.
+	RequestIDs []RequestID
+}
+
+func (err ErrStillResponsible) Error() string {
+	return fmt.Sprintf("worker is still responsible for %d unresolved requests", len(err.RequestIDs))
+}
+
+// ErrWorkerDropped is returned by [Worker.Reset] if the worker has already
+// been dropped, such as by [Worker.Cancel] or [Worker.Close], and so cannot
+// be used again.
+type ErrWorkerDropped struct {
+	// Cause is the error that the worker was dropped with, if any, such as
+	// the error passed to [Worker.Cancel].
+	Cause error
+}
+
+func (err ErrWorkerDropped) Error() string {
+	if err.Cause != nil {
+		return "worker was dropped: " + err.Cause.Error()
+	}
+	return "worker was dropped"
+}
+
+// Unwrap returns the cause of the error, if any.
+func (err ErrWorkerDropped) Unwrap() error {
+	return err.Cause
+}
+
+// ErrAlreadyResolved is returned by [Resolver.TryReport] if the request was
+// already resolved by an earlier report.
+type ErrAlreadyResolved struct {
+	// RequestID is the request that was already resolved.
+	RequestID RequestID
+}
+
+func (err ErrAlreadyResolved) Error() string {
+	return "request resolved multiple times"
+}
+
+// ErrNotResponsible is returned by [Resolver.TryReport] if the worker
+// reporting the result is not the worker that's responsible for the request.
+type ErrNotResponsible struct {
+	// RequestID is the request that the worker attempted to resolve.
+	RequestID RequestID
+
+	// Expected is the worker that was responsible for the request. This is
+	// the zero WorkerID if the request was created already resolved, and
+	// so no worker has ever been responsible for it.
+	Expected WorkerID
+
+	// Actual is the worker that attempted to resolve the request.
+	Actual WorkerID
+}
+
+func (err ErrNotResponsible) Error() string {
+	// We use the internal representation of the workers here, if they are
+	// still live, so that we can include their names.
+	return fmt.Sprintf(
+		"request was resolved by worker %s, but %s was responsible",
+		err.Actual.ptr.Value(), err.Expected.ptr.Value(),
+	)
+}
+
+// ErrAwaitBudgetExceeded is returned by [Promise.Await] if the requesting
+// worker was created by [NewWorkerWithAwaitBudget] and has already blocked
+// as many times as its budget allows.
+type ErrAwaitBudgetExceeded struct {
+	// RequestID is the request that the worker would have blocked on.
+	RequestID RequestID
+
+	// Budget is the number of times the worker was allowed to block.
+	Budget int
+}
+
+func (err ErrAwaitBudgetExceeded) Error() string {
+	return fmt.Sprintf("worker exceeded its budget of %d blocking awaits", err.Budget)
+}
+
+// Retryable returns false, because the worker's budget never increases.
+func (err ErrAwaitBudgetExceeded) Retryable() bool {
+	return false
+}
+
+// ErrKey is returned by [GatherMap] to report which key's promise failed.
+type ErrKey[K comparable] struct {
+	// Key is the key of the promise that failed.
+	Key K
+
+	// Err is the error that the promise failed with.
+	Err error
+}
+
+func (err ErrKey[K]) Error() string {
+	return fmt.Sprintf("%v: %s", err.Key, err.Err)
+}
+
+// Unwrap returns the error that the promise failed with.
+func (err ErrKey[K]) Unwrap() error {
+	return err.Err
+}
+
+// ErrAbandoned is returned by [Promise.Await] if the worker responsible for
+// the request gave up on it by calling [Resolver.Abandon].
+type ErrAbandoned struct {
+	// RequestID is the request that was abandoned.
+	RequestID RequestID
+}
+
+func (err ErrAbandoned) Error() string {
+	return "responsible worker abandoned the request"
+}
+
+// ErrTimeout is returned by [Promise.AwaitTimeout] if the request was not
+// resolved before the timeout elapsed, and by [Once.DoTimeout] if the shared
+// computation did not complete before its timeout elapsed.
+type ErrTimeout struct {
+	// RequestID is the request that was being awaited, or the request
+	// representing the shared computation that timed out.
+	RequestID RequestID
+}
+
+func (err ErrTimeout) Error() string {
+	return "timed out waiting for request to be resolved"
+}
+
+// Unwrap returns [context.DeadlineExceeded], so that callers can treat this
+// error in the same way as other deadline errors.
+func (err ErrTimeout) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// Retryable returns true, because the request might be resolved if awaited
+// again.
+func (err ErrTimeout) Retryable() bool {
+	return true
+}
+
+// ErrConcurrentAwait is the value that [Promise.Await] and similar functions
+// panic with if a worker is used to await a promise while it's already
+// awaiting a different promise on another goroutine.
+//
+// Each worker can await only one promise at a time, so this always indicates
+// a bug in the caller. It's a panic rather than an error so that the bug
+// can't be silently ignored, but code that recovers panics at some boundary
+// can use this type to report it in more detail.
+type ErrConcurrentAwait struct {
+	// Worker is the string representation of the worker that was used
+	// concurrently, including its name if it has one.
+	Worker string
+
+	// Awaiting is the request that the worker was already awaiting. This is
+	// [NoRequest] if the worker had already stopped awaiting that request by
+	// the time the problem was detected.
+	Awaiting RequestID
+
+	// Requested is the request that the worker was asked to await while
+	// it was already awaiting the other one. This is [NoRequest] if the
+	// worker was asked to await several requests at once, as with
+	// [AwaitAny].
+	Requested RequestID
+}
+
+func (err ErrConcurrentAwait) Error() string {
+	return fmt.Sprintf("worker %s awaits multiple promises", err.Worker)
 }
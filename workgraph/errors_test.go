@@ -0,0 +1,173 @@
+package workgraph_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/apparentlymart/go-workgraph/workgraph"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want bool
+	}{
+		"nil": {
+			nil,
+			false,
+		},
+		"plain error": {
+			errors.New("oops"),
+			true,
+		},
+		"self-dependency": {
+			workgraph.ErrSelfDependency{},
+			false,
+		},
+		"wrapped self-dependency": {
+			fmt.Errorf("wrapped: %w", workgraph.ErrSelfDependency{}),
+			false,
+		},
+		"unresolved": {
+			workgraph.ErrUnresolved{},
+			true,
+		},
+		"custom non-retryable": {
+			testRetryableError{retryable: false},
+			false,
+		},
+		"custom retryable": {
+			testRetryableError{retryable: true},
+			true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := workgraph.IsRetryable(test.err); got != test.want {
+				t.Errorf("wrong result %t; want %t", got, test.want)
+			}
+		})
+	}
+}
+
+type testRetryableError struct {
+	retryable bool
+}
+
+func (err testRetryableError) Error() string {
+	return "test error"
+}
+
+func (err testRetryableError) Retryable() bool {
+	return err.retryable
+}
+
+func TestErrUnresolved_cause(t *testing.T) {
+	w := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](w)
+	errShutdown := errors.New("shutting down")
+	w.Cancel(errShutdown)
+
+	_, err := promise.Await(workgraph.NewWorker())
+	var unresolvedErr workgraph.ErrUnresolved
+	if !errors.As(err, &unresolvedErr) {
+		t.Fatalf("wrong error %v; want %T", err, unresolvedErr)
+	}
+	if unresolvedErr.RequestID != resolver.RequestID() {
+		t.Errorf("error has the wrong RequestID")
+	}
+	if !errors.Is(err, errShutdown) {
+		t.Errorf("error does not wrap its cause")
+	}
+	if got, want := err.Error(), "responsible worker was dropped before request was resolved: shutting down"; got != want {
+		t.Errorf("wrong error message\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestErrSelfDependency_labels(t *testing.T) {
+	w := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequestNamed[string](w, "example")
+	if got, want := resolver.RequestID().Label(), "example"; got != want {
+		t.Errorf("wrong label %q; want %q", got, want)
+	}
+
+	_, err := promise.Await(w)
+	if got, want := err.Error(), "self-dependency detected between example"; got != want {
+		t.Errorf("wrong error message\ngot:  %s\nwant: %s", got, want)
+	}
+
+	_, unnamed := workgraph.NewRequest[string](w)
+	_, err = unnamed.Await(w)
+	if got, want := err.Error(), "self-dependency detected"; got != want {
+		t.Errorf("wrong error message for unnamed request\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestErrSelfDependency_cyclePath(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	workerA := workgraph.NewWorker()
+	workerB := workgraph.NewWorker()
+	resolver1, promise1 := workgraph.NewRequest[string](workerA)
+	resolver2, promise2 := workgraph.NewRequest[string](workerB)
+	resolver3, promise3 := workgraph.NewRequest[string](mainWorker)
+
+	// We make workerA await request 2 and then workerB await request 3, so
+	// that mainWorker's await of request 1 is what completes the cycle.
+	blocked := make(chan struct{})
+	onBlock := func(workgraph.RequestID) { blocked <- struct{}{} }
+	workerA.SetOnBlock(onBlock)
+	workerB.SetOnBlock(onBlock)
+	go promise2.Await(workerA)
+	<-blocked
+	go promise3.Await(workerB)
+	<-blocked
+
+	_, err := promise1.Await(mainWorker)
+	selfDepErr, ok := err.(workgraph.ErrSelfDependency)
+	if !ok {
+		t.Fatalf("wrong error type %T; want %T", err, selfDepErr)
+	}
+	want := []workgraph.RequestID{
+		resolver1.RequestID(),
+		resolver2.RequestID(),
+		resolver3.RequestID(),
+		resolver1.RequestID(),
+	}
+	if diff := cmp.Diff(want, selfDepErr.CyclePath); diff != "" {
+		t.Error("wrong cycle path\n" + diff)
+	}
+}
+
+func TestErrorsIs(t *testing.T) {
+	w := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](w)
+	_, err := promise.Await(w)
+	wrapped := fmt.Errorf("wrapped: %w", err)
+	if !errors.Is(wrapped, workgraph.ErrSelfDependency{}) {
+		t.Errorf("errors.Is does not match ErrSelfDependency")
+	}
+	if errors.Is(wrapped, workgraph.ErrUnresolved{}) {
+		t.Errorf("errors.Is matches ErrUnresolved for ErrSelfDependency")
+	}
+	var selfDepErr workgraph.ErrSelfDependency
+	if !errors.As(wrapped, &selfDepErr) {
+		t.Fatalf("errors.As does not match ErrSelfDependency")
+	}
+	if got, want := selfDepErr.RequestIDs, []workgraph.RequestID{resolver.RequestID()}; !cmp.Equal(got, want) {
+		t.Errorf("wrong request ids %s; want %s", got, want)
+	}
+
+	wrapped = fmt.Errorf("wrapped: %w", workgraph.ErrUnresolved{
+		RequestID: resolver.RequestID(),
+		Cause:     errors.New("cause"),
+	})
+	if !errors.Is(wrapped, workgraph.ErrUnresolved{}) {
+		t.Errorf("errors.Is does not match ErrUnresolved")
+	}
+	if errors.Is(wrapped, workgraph.ErrSelfDependency{}) {
+		t.Errorf("errors.Is matches ErrSelfDependency for ErrUnresolved")
+	}
+}
@@ -0,0 +1,16 @@
+package workgraph
+
+import (
+	"time"
+)
+
+// SetDebounceAfterFunc replaces the function that [Once.Reset] uses to end
+// a debounce window, so that tests can end the window explicitly instead of
+// depending on the wall clock. The returned function restores the original.
+func SetDebounceAfterFunc(f func(time.Duration, func())) (restore func()) {
+	prev := debounceAfterFunc
+	debounceAfterFunc = f
+	return func() {
+		debounceAfterFunc = prev
+	}
+}
@@ -0,0 +1,156 @@
+package workgraph
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// Group is a collection of [Once] objects indexed by a key, so that a
+// computation can be deduplicated separately for each distinct key.
+//
+// The zero value of Group is ready to use, and retains the result for each
+// key until it's passed to [Group.Forget]. Use [NewGroupWithLimit] to create
+// a group that discards older results automatically. A Group must not be
+// copied after first use.
+type Group[K comparable, T any] struct {
+	mu      sync.Mutex
+	entries map[K]*groupEntry[K, T]
+
+	// recent orders the entries from most to least recently awaited, so
+	// that we can choose which to evict when maxEntries is exceeded.
+	recent     list.List
+	maxEntries int
+}
+
+type groupEntry[K comparable, T any] struct {
+	once Once[T]
+
+	// waiters is the number of calls currently waiting for the result of
+	// once, which must be zero for the entry to be evicted.
+	waiters int
+
+	// elem is this entry's element in the group's recent list, whose value
+	// is the entry's key.
+	elem *list.Element
+}
+
+// NewGroupWithLimit returns a new [Group] that retains the results for at
+// most the given number of keys.
+//
+// When the number of keys exceeds the limit, the group forgets the keys that
+// were least recently awaited, as if by calling [Group.Forget], until it is
+// back within the limit. Only keys whose computation has completed and that
+// have no callers currently waiting are eligible to be forgotten, and so
+// the number of keys can temporarily exceed the limit while there are more
+// computations in progress than the limit allows.
+//
+// A limit of zero or less means that there is no limit, which is the same as
+// the zero value of Group.
+func NewGroupWithLimit[K comparable, T any](maxEntries int) *Group[K, T] {
+	return &Group[K, T]{
+		maxEntries: maxEntries,
+	}
+}
+
+// Do calls the function f if and only if Do is being called for the first
+// time with the given key on this instance of [Group], or for the first time
+// since that key was passed to [Group.Forget].
+//
+// Each key behaves as a separate [Once], and so the calls for a particular
+// key all return the result of the first call's function. If f directly or
+// indirectly causes another call to Do with the same key then all affected
+// calls fail with [ErrSelfDependency], but f may freely call Do with other
+// keys as long as that doesn't create a cycle.
+//
+// If forWorker was created by [NewWorkerContext] then the wait for the
+// result honors that context, as with [Group.DoContext].
+func (g *Group[K, T]) Do(forWorker *Worker, key K, f func(*Worker) (T, error)) (T, error) {
+	return g.DoContext(workerContext(forWorker), forWorker, key, f)
+}
+
+// DoContext is like [Group.Do] except that the wait for the result honors
+// the given context, returning the context's error if it is cancelled before
+// the result is available.
+//
+// As with [Once.DoContext], the context affects only the waiting done by
+// this particular call, and so the computation for the given key continues
+// on behalf of any other callers even if this call's context is cancelled.
+func (g *Group[K, T]) DoContext(ctx context.Context, forWorker *Worker, key K, f func(*Worker) (T, error)) (T, error) {
+	entry := g.startWait(key)
+	defer g.endWait(entry)
+	return entry.once.DoContext(ctx, forWorker, f)
+}
+
+// Forget discards any result for the given key, so that the next call to
+// [Group.Do] with that key will run its given function again.
+//
+// As with [Once.Reset], Forget does not affect callers that are already
+// waiting for the previous result.
+func (g *Group[K, T]) Forget(key K) {
+	g.mu.Lock()
+	if entry, ok := g.entries[key]; ok {
+		g.removeLocked(key, entry)
+	}
+	g.mu.Unlock()
+}
+
+// startWait returns the entry for the given key, creating it if necessary,
+// and registers a new waiter for it. The caller must call endWait with the
+// same entry once it's finished waiting.
+func (g *Group[K, T]) startWait(key K) *groupEntry[K, T] {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.entries == nil {
+		g.entries = make(map[K]*groupEntry[K, T])
+	}
+	entry, ok := g.entries[key]
+	if !ok {
+		entry = &groupEntry[K, T]{}
+		entry.elem = g.recent.PushFront(key)
+		g.entries[key] = entry
+	} else {
+		g.recent.MoveToFront(entry.elem)
+	}
+	entry.waiters++
+	return entry
+}
+
+func (g *Group[K, T]) endWait(entry *groupEntry[K, T]) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	entry.waiters--
+	if entry.elem == nil {
+		return // The entry was already forgotten while we were waiting.
+	}
+	g.recent.MoveToFront(entry.elem)
+	g.evictLocked()
+}
+
+// evictLocked forgets the least recently awaited idle entries until the
+// group is within its limit or there are no more idle entries. This must be
+// called only while holding g.mu.
+func (g *Group[K, T]) evictLocked() {
+	if g.maxEntries <= 0 {
+		return
+	}
+	elem := g.recent.Back()
+	for len(g.entries) > g.maxEntries && elem != nil {
+		prev := elem.Prev()
+		key := elem.Value.(K)
+		entry := g.entries[key]
+		if entry.waiters == 0 {
+			if _, _, resolved := entry.once.ResolvedValue(); resolved {
+				g.removeLocked(key, entry)
+			}
+		}
+		elem = prev
+	}
+}
+
+// removeLocked must be called only while holding g.mu.
+func (g *Group[K, T]) removeLocked(key K, entry *groupEntry[K, T]) {
+	g.recent.Remove(entry.elem)
+	entry.elem = nil
+	delete(g.entries, key)
+}
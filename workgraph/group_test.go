@@ -0,0 +1,149 @@
+package workgraph_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/apparentlymart/go-workgraph/workgraph"
+)
+
+func TestGroup(t *testing.T) {
+	var group workgraph.Group[string, string]
+	var calls atomic.Int32
+	f := func(key string) func(*workgraph.Worker) (string, error) {
+		return func(w *workgraph.Worker) (string, error) {
+			calls.Add(1)
+			return "Hello, " + key + "!", nil
+		}
+	}
+
+	for range 2 {
+		for _, key := range []string{"a", "b"} {
+			got, err := group.Do(workgraph.NewWorker(), key, f(key))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if want := "Hello, " + key + "!"; got != want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+			}
+		}
+	}
+	if got, want := calls.Load(), int32(2); got != want {
+		t.Errorf("functions called %d times; want %d", got, want)
+	}
+
+	group.Forget("a")
+	if _, err := group.Do(workgraph.NewWorker(), "a", f("a")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := calls.Load(), int32(3); got != want {
+		t.Errorf("functions called %d times after Forget; want %d", got, want)
+	}
+}
+
+func TestGroup_selfDependency(t *testing.T) {
+	var group workgraph.Group[string, string]
+	var f func(*workgraph.Worker) (string, error)
+	f = func(w *workgraph.Worker) (string, error) {
+		// Awaiting another key is fine, but then that one depends on
+		// this one.
+		return group.Do(w, "b", func(w *workgraph.Worker) (string, error) {
+			return group.Do(w, "a", f)
+		})
+	}
+
+	_, err := group.Do(workgraph.NewWorker(), "a", f)
+	selfDepErr, ok := err.(workgraph.ErrSelfDependency)
+	if !ok {
+		t.Fatalf("wrong error type %T; want %T", err, selfDepErr)
+	}
+	if got, want := len(selfDepErr.RequestIDs), 2; got != want {
+		t.Errorf("wrong number of request ids %d; want %d", got, want)
+	}
+}
+
+func TestGroup_doContext(t *testing.T) {
+	var group workgraph.Group[string, string]
+	release := make(chan struct{})
+	f := func(w *workgraph.Worker) (string, error) {
+		<-release
+		return "Hello, world!", nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := group.DoContext(ctx, workgraph.NewWorker(), "a", f)
+	if err != context.Canceled {
+		t.Errorf("wrong error %v; want %v", err, context.Canceled)
+	}
+
+	// The computation continues for other callers even though the first
+	// caller gave up.
+	close(release)
+	got, err := group.DoContext(context.Background(), workgraph.NewWorker(), "a", f)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "Hello, world!"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestGroup_doWorkerContext(t *testing.T) {
+	var group workgraph.Group[string, string]
+	release := make(chan struct{})
+	defer close(release)
+	f := func(w *workgraph.Worker) (string, error) {
+		<-release
+		return "Hello, world!", nil
+	}
+
+	// Do honors the context of a worker created by NewWorkerContext.
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err := group.Do(workgraph.NewWorkerContext(ctx), "a", f)
+	if err != context.Canceled {
+		t.Errorf("wrong error %v; want %v", err, context.Canceled)
+	}
+}
+
+func TestGroupWithLimit(t *testing.T) {
+	group := workgraph.NewGroupWithLimit[int, int](2)
+	var calls atomic.Int32
+	f := func(w *workgraph.Worker) (int, error) {
+		return int(calls.Add(1)), nil
+	}
+
+	// The function for key 0 blocks until we release it, so that its
+	// entry is not eligible for eviction while we fill up the group.
+	started := make(chan struct{})
+	release := make(chan struct{})
+	blocked := make(chan int)
+	go func() {
+		got, _ := group.Do(workgraph.NewWorker(), 0, func(w *workgraph.Worker) (int, error) {
+			close(started)
+			<-release
+			return -1, nil
+		})
+		blocked <- got
+	}()
+	<-started
+
+	group.Do(workgraph.NewWorker(), 1, f) // call 1
+	group.Do(workgraph.NewWorker(), 2, f) // call 2; evicts key 1 but not key 0
+
+	close(release)
+	if got, want := <-blocked, -1; got != want {
+		t.Errorf("wrong result for in-flight key %d; want %d", got, want)
+	}
+
+	// Key 2 is still retained, but key 1 was evicted.
+	if got, _ := group.Do(workgraph.NewWorker(), 2, f); got != 2 {
+		t.Errorf("wrong result for key 2 %d; want 2", got)
+	}
+	if got, _ := group.Do(workgraph.NewWorker(), 1, f); got != 3 {
+		t.Errorf("wrong result for key 1 %d; want 3", got)
+	}
+}
@@ -0,0 +1,64 @@
+package workgraph
+
+import (
+	"runtime"
+	"time"
+)
+
+// EnableLeakDetection starts a background goroutine that periodically
+// checks whether any goroutine is blocked awaiting a request whose
+// responsible worker has not yet been dropped, and if so asks the Go runtime
+// to run a garbage collection cycle. The returned function stops the
+// background goroutine.
+//
+// Requests whose responsible worker is no longer reachable fail with
+// [ErrUnresolved] only once the garbage collector notices that the worker
+// is unreachable, which might not happen for some time in a program that
+// is mostly idle because its goroutines are all blocked. Forcing a
+// collection while something is waiting ensures that such requests fail
+// within about one interval of their worker being abandoned.
+//
+// There's no way to determine whether an object is reachable without running
+// the garbage collector, and so this can't distinguish an abandoned worker
+// from one that is just taking a long time. Each collection is relatively
+// expensive, so leak detection is disabled by default and is intended
+// primarily for use during development and testing.
+func EnableLeakDetection(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if anyWorkerAwaited() {
+					runtime.GC()
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
+
+// anyWorkerAwaited returns true if any live worker is responsible for a
+// request that at least one goroutine is currently blocked awaiting.
+func anyWorkerAwaited() bool {
+	found := false
+	liveWorkers.Range(func(k, _ any) bool {
+		wi := k.(*workerInner)
+		wi.mu.Lock()
+		for req := range wi.responsibleFor {
+			if req.waiters.Load() != 0 {
+				found = true
+				break
+			}
+		}
+		wi.mu.Unlock()
+		return !found
+	})
+	return found
+}
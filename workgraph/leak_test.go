@@ -0,0 +1,25 @@
+package workgraph_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apparentlymart/go-workgraph/workgraph"
+)
+
+func TestEnableLeakDetection(t *testing.T) {
+	stop := workgraph.EnableLeakDetection(10 * time.Millisecond)
+	defer stop()
+
+	promise := func() workgraph.Promise[string] {
+		// The worker becomes unreachable once this function returns,
+		// without ever resolving its request.
+		_, promise := workgraph.NewRequest[string](workgraph.NewWorker())
+		return promise
+	}()
+
+	_, err := promise.AwaitTimeout(nil, 5*time.Second)
+	if _, ok := err.(workgraph.ErrUnresolved); !ok {
+		t.Fatalf("wrong error %v; want %T", err, workgraph.ErrUnresolved{})
+	}
+}
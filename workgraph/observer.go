@@ -0,0 +1,45 @@
+package workgraph
+
+import (
+	"sync/atomic"
+)
+
+// Observer is implemented by types that want to be notified when workers
+// block awaiting requests, such as for tracing or profiling.
+//
+// Use [SetObserver] to register an observer.
+type Observer interface {
+	// OnAwaitStart is called when a worker is about to block awaiting the
+	// given request, after checking for self-dependency.
+	OnAwaitStart(worker WorkerID, request RequestID)
+
+	// OnAwaitEnd is called when the worker stops waiting for the given
+	// request, either because the request was resolved or because the
+	// wait was cancelled. err is the error that the await returns, if any.
+	OnAwaitEnd(worker WorkerID, request RequestID, err error)
+}
+
+// SetObserver registers an [Observer] to be notified when any worker blocks
+// awaiting a request, replacing any observer previously registered. Passing
+// nil removes any existing observer.
+//
+// Awaits of requests that are already resolved do not block, and so the
+// observer is not notified of them. Awaits that began before the observer
+// was registered might produce an OnAwaitEnd call without a corresponding
+// OnAwaitStart call.
+//
+// The observer's methods are called on the awaiting goroutine without
+// holding any of this package's locks, but while the worker is registered
+// as awaiting the request. They must therefore not use the worker to await
+// anything, and they should return quickly because they delay the awaiting
+// worker.
+func SetObserver(o Observer) {
+	if o == nil {
+		observer.Store(nil)
+		return
+	}
+	observer.Store(&o)
+}
+
+// observer is the observer registered by [SetObserver], if any.
+var observer atomic.Pointer[Observer]
@@ -0,0 +1,74 @@
+package workgraph_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/apparentlymart/go-workgraph/workgraph"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+type testObserver struct {
+	mu     sync.Mutex
+	events []testObserverEvent
+}
+
+type testObserverEvent struct {
+	Start   bool
+	Worker  workgraph.WorkerID
+	Request workgraph.RequestID
+	Err     error
+}
+
+func (o *testObserver) OnAwaitStart(worker workgraph.WorkerID, request workgraph.RequestID) {
+	o.mu.Lock()
+	o.events = append(o.events, testObserverEvent{Start: true, Worker: worker, Request: request})
+	o.mu.Unlock()
+}
+
+func (o *testObserver) OnAwaitEnd(worker workgraph.WorkerID, request workgraph.RequestID, err error) {
+	o.mu.Lock()
+	o.events = append(o.events, testObserverEvent{Worker: worker, Request: request, Err: err})
+	o.mu.Unlock()
+}
+
+func TestSetObserver(t *testing.T) {
+	observer := &testObserver{}
+	workgraph.SetObserver(observer)
+	defer workgraph.SetObserver(nil)
+
+	mainWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+	errFailed := errors.New("failed")
+	blocked := make(chan struct{})
+	mainWorker.SetOnBlock(func(workgraph.RequestID) {
+		close(blocked)
+	})
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		<-blocked
+		resolver.ReportError(w, errFailed)
+	}, resolver)
+	promise.Await(mainWorker)
+	// Awaiting an already-resolved request does not block.
+	promise.Await(mainWorker)
+
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+	want := []testObserverEvent{
+		{Start: true, Worker: mainWorker.ID(), Request: resolver.RequestID()},
+		{Worker: mainWorker.ID(), Request: resolver.RequestID(), Err: errFailed},
+	}
+	// Other tests might have left goroutines running that could also
+	// generate events, so we'll consider only those for our worker.
+	var got []testObserverEvent
+	for _, event := range observer.events {
+		if event.Worker == mainWorker.ID() {
+			got = append(got, event)
+		}
+	}
+	if diff := cmp.Diff(want, got, cmpopts.EquateErrors()); diff != "" {
+		t.Error("wrong events\n" + diff)
+	}
+}
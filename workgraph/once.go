@@ -1,7 +1,9 @@
 package workgraph
 
 import (
+	"context"
 	"sync"
+	"time"
 )
 
 // Once is similar in principle to the standard library's [sync.Once], but
@@ -12,6 +14,9 @@ type Once[T any] struct {
 	mu      sync.Mutex
 	promise Promise[T]
 	req_id  RequestID
+
+	resetWindow  time.Duration
+	resetPending bool
 }
 
 // Do calls the function f if and only if Do is being called for the first
@@ -25,23 +30,96 @@ type Once[T any] struct {
 // that is responsible for providing the return value. If f directly or
 // indirectly causes another call to Do on the same Once then all affected
 // calls will fail with [ErrSelfDependency].
+//
+// If f panics then the panic is recovered and all calls fail with
+// [ErrPanic] instead of crashing the program.
+//
+// If forWorker was created by [NewWorkerContext] then the wait for the
+// result honors that context, as with [Once.DoContext].
 func (o *Once[T]) Do(forWorker *Worker, f func(*Worker) (T, error)) (T, error) {
+	return o.DoContext(workerContext(forWorker), forWorker, f)
+}
+
+// DoContext is like [Once.Do] except that the wait for the result honors
+// the given context, returning the context's error if it is cancelled before
+// the result is available.
+//
+// The context affects only the waiting done by this particular call. If this
+// is the first call then f still starts running in its own worker, and
+// continues running even if the context is cancelled, so that its result is
+// available to other callers and to subsequent calls.
+func (o *Once[T]) DoContext(ctx context.Context, forWorker *Worker, f func(*Worker) (T, error)) (T, error) {
+	promise := o.start(forWorker, 0, f)
+	return promise.AwaitContext(ctx, forWorker)
+}
+
+// DoTimeout is like [Once.Do] except that if this is the first call then
+// the computation it starts is given only the given duration to complete.
+//
+// If f has not returned once the timeout has elapsed then the shared result
+// fails with [ErrTimeout], and so all callers waiting for it and all
+// subsequent calls receive that error. The context returned by the
+// [Worker.Context] method of the worker passed to f is also cancelled at
+// that point, so that f can stop work that's no longer needed, and any
+// awaits that f is blocked in return early.
+//
+// The timeout is decided only by the first call. If the computation has
+// already started then DoTimeout behaves just like Do, and its timeout
+// is ignored.
+func (o *Once[T]) DoTimeout(forWorker *Worker, timeout time.Duration, f func(*Worker) (T, error)) (T, error) {
+	promise := o.start(forWorker, timeout, f)
+	return promise.Await(forWorker)
+}
+
+// start begins the computation if it hasn't already started, and then
+// returns the promise for its result. If timeout is positive then a
+// computation started by this call fails with [ErrTimeout] if f does not
+// return within that duration.
+func (o *Once[T]) start(forWorker *Worker, timeout time.Duration, f func(*Worker) (T, error)) Promise[T] {
 	o.mu.Lock()
-	if o.promise.isNil() {
-		// This is the first call, so we'll establish the inner request
-		// and start executing the function in a separate goroutine.
-		resolver, promise := NewRequest[T](forWorker)
-		o.promise = promise
-		o.req_id = resolver.RequestID()
+	defer o.mu.Unlock()
+	if !o.promise.isNil() {
+		// We must capture the promise while still holding the lock, because
+		// [Once.Reset] could replace it as soon as we release the lock.
+		return o.promise
+	}
+
+	// This is the first call, so we'll establish the inner request
+	// and start executing the function in a separate goroutine.
+	resolver, promise := NewRequest[T](forWorker)
+	o.promise = promise
+	o.req_id = resolver.RequestID()
+	if timeout <= 0 {
 		WithNewAsyncWorker(func(w *Worker) {
-			ret, err := f(w)
+			ret, err := callRecovering(w, f)
 			resolver.Report(w, ret, err)
 		}, resolver)
-	} else {
+		return promise
 	}
-	o.mu.Unlock()
 
-	return o.promise.Await(forWorker)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	timedOut := func() bool {
+		if ctx.Err() != context.DeadlineExceeded {
+			return false
+		}
+		// If f already reported its result then this does nothing, and if
+		// it tries to report its result later then that is ignored.
+		resolver.inner.resolveUsageFault(ErrTimeout{RequestID: resolver.RequestID()})
+		return true
+	}
+	context.AfterFunc(ctx, func() { timedOut() })
+	w := NewWorkerContext(ctx, resolver)
+	go func() {
+		defer cancel()
+		ret, err := callRecovering(w, f)
+		// f might have returned early only because its awaits were
+		// cancelled by the timeout, in which case we report the timeout
+		// rather than whatever error f returned.
+		if !timedOut() {
+			resolver.Report(w, ret, err)
+		}
+	}()
+	return promise
 }
 
 // RequestID returns the identifier of the internal request that represents
@@ -55,6 +133,185 @@ func (o *Once[T]) RequestID() RequestID {
 	return o.req_id
 }
 
+// Started returns true if [Once.Do] has been called at least once since the
+// Once was created or most recently reset, regardless of whether the
+// computation it started has completed yet.
+//
+// A Once populated by [Once.Seed] is also considered to have started.
+func (o *Once[T]) Started() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return !o.promise.isNil()
+}
+
+// Promise returns the promise for the result of the computation started by
+// [Once.Do], so that the result can be shared with code that should not be
+// able to start or reset the computation itself.
+//
+// If Do has not yet been called then this returns a promise that is already
+// resolved with [ErrNotStarted]. A later call to Do does not affect a promise
+// that was previously returned.
+func (o *Once[T]) Promise() Promise[T] {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.promise.isNil() {
+		return Promise[T]{
+			inner: newSettledRequestInner(newUsageFaultResult(ErrNotStarted{})),
+		}
+	}
+	return o.promise
+}
+
+// ResolvedValue returns the result of the computation started by [Once.Do],
+// if that computation has completed.
+//
+// The final return value is false if Do has not yet been called or if the
+// computation it started is still in progress, in which case the other
+// return values are meaningless.
+func (o *Once[T]) ResolvedValue() (T, error, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.promise.isNil() {
+		var zero T
+		return zero, nil, false
+	}
+	result := o.promise.inner.result.Load()
+	if result == nil {
+		var zero T
+		return zero, nil, false
+	}
+	value, err := resultRet[T](result)
+	return value, err, true
+}
+
+// Seed populates the Once with a result as if [Once.Do] had already been
+// called with a function that returned the given value and error, so that
+// subsequent calls to Do return that result without calling their function.
+//
+// This is intended for restoring a result previously obtained using
+// [Once.ResolvedValue], such as when loading memoized results from a cache.
+//
+// Seed has no effect if Do has already been called, regardless of whether
+// the computation it started has completed yet.
+func (o *Once[T]) Seed(value T, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.promise.isNil() {
+		return
+	}
+	inner := newSettledRequestInner(newExplicitResult(nil, value, err))
+	o.promise = Promise[T]{inner: inner}
+	o.req_id = inner.RequestID()
+}
+
+// Reset discards the result of any previous call to [Once.Do], so that the
+// next call will run its given function again.
+//
+// Reset does not affect callers that are already waiting for the previous
+// result, or promises previously returned by [Once.Promise]: those still
+// observe the result of the previous computation once it completes. If the
+// previous computation is still in progress when Reset is called then it
+// continues running, and so f may be running twice concurrently if Do is
+// called again before it completes. The two computations are independent,
+// and so each caller of Do receives the result of whichever computation was
+// current when it called Do.
+//
+// If [Once.DebounceReset] has been used to set a debounce window then the
+// reset is delayed until the end of that window.
+func (o *Once[T]) Reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.resetWindow > 0 {
+		if !o.resetPending {
+			o.resetPending = true
+			debounceAfterFunc(o.resetWindow, o.resetDebounced)
+		}
+		return
+	}
+	o.resetLocked()
+}
+
+// DebounceReset arranges for any future calls to [Once.Reset] to be
+// coalesced so that at most one reset takes effect per window.
+//
+// The first call to Reset after this starts a timer for the given window,
+// and then the reset takes effect only once that window has elapsed. Any
+// other calls to Reset during the window are absorbed into the pending reset.
+// Until the window has elapsed, calls to [Once.Do] continue to return the
+// previous result, and then the first call to Do after the window elapses
+// runs its function again.
+//
+// A window of zero or less disables debouncing, so that subsequent calls to
+// Reset take effect immediately. A reset that is already pending when the
+// window is changed still takes effect at the end of its original window.
+func (o *Once[T]) DebounceReset(window time.Duration) {
+	o.mu.Lock()
+	o.resetWindow = window
+	o.mu.Unlock()
+}
+
+// debounceAfterFunc arranges for f to be called once the given duration
+// has elapsed, to end a debounce window started by [Once.Reset]. Tests can
+// replace this to control when the window ends.
+var debounceAfterFunc = func(d time.Duration, f func()) {
+	time.AfterFunc(d, f)
+}
+
+func (o *Once[T]) resetDebounced() {
+	o.mu.Lock()
+	o.resetPending = false
+	o.resetLocked()
+	o.mu.Unlock()
+}
+
+// resetLocked must be called only while holding o.mu.
+func (o *Once[T]) resetLocked() {
+	o.promise = Promise[T]{}
+	o.req_id = NoRequest
+}
+
+// OnceValue is a variant of [Once] for computations that cannot fail, so
+// that callers need not handle an error return value on every call.
+//
+// Even a computation that cannot fail can still be affected by problems in
+// how the workgraph is used, such as a self-dependency. In that case
+// [OnceValue.Do] returns the zero value of T, and the error is available
+// from [OnceValue.Err].
+type OnceValue[T any] struct {
+	once Once[T]
+}
+
+// Do calls the function f if and only if Do is being called for the first
+// time on this instance of [OnceValue], and then returns the value that the
+// first call's function returned.
+//
+// This behaves as [Once.Do] except that if the result cannot be produced,
+// such as because f directly or indirectly depends on its own result, Do
+// returns the zero value of T and [OnceValue.Err] returns the error.
+func (o *OnceValue[T]) Do(forWorker *Worker, f func(*Worker) T) T {
+	ret, _ := o.once.Do(forWorker, func(w *Worker) (T, error) {
+		return f(w), nil
+	})
+	return ret
+}
+
+// Err returns the error that prevented the computation started by
+// [OnceValue.Do] from producing a value, such as [ErrSelfDependency].
+//
+// Err returns nil if Do has not yet been called, if the computation is still
+// in progress, or if the computation completed successfully.
+func (o *OnceValue[T]) Err() error {
+	_, err, _ := o.once.ResolvedValue()
+	return err
+}
+
+// RequestID returns the identifier of the internal request that represents
+// the completion of all calls to [OnceValue.Do] on this object, as with
+// [Once.RequestID].
+func (o *OnceValue[T]) RequestID() RequestID {
+	return o.once.RequestID()
+}
+
 // OnceFunc returns a function that, when called for the first time, will
 // run f using a newly-created [Worker], and then that and all subsequent
 // calls will return whatever that function returns.
@@ -1,10 +1,12 @@
 package workgraph_test
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/apparentlymart/go-workgraph/workgraph"
 	"github.com/google/go-cmp/cmp"
@@ -73,8 +75,8 @@ func TestOnce_selfDependencyDirect(t *testing.T) {
 	if !ok {
 		t.Fatalf("wrong error type %T; want %T", err, selfDepErr)
 	}
-	wantResultIDs := []workgraph.RequestID{once.RequestID()}
-	if diff := cmp.Diff(wantResultIDs, selfDepErr.RequestIDs); diff != "" {
+	wantRequestIDs := []workgraph.RequestID{once.RequestID()}
+	if diff := cmp.Diff(wantRequestIDs, selfDepErr.RequestIDs); diff != "" {
 		t.Error("wrong request ids\n" + diff)
 	}
 }
@@ -132,3 +134,292 @@ func TestOnceFunc_selfDependencyDirect(t *testing.T) {
 		t.Errorf("wrong number of request ids %d; want %d", got, want)
 	}
 }
+
+func TestOnce_debounceReset(t *testing.T) {
+	// We end the debounce window explicitly rather than waiting for it,
+	// so that the test doesn't depend on timing.
+	var pending []func()
+	restore := workgraph.SetDebounceAfterFunc(func(d time.Duration, f func()) {
+		pending = append(pending, f)
+	})
+	defer restore()
+
+	var once workgraph.Once[int]
+	var calls atomic.Int32
+	f := func(w *workgraph.Worker) (int, error) {
+		return int(calls.Add(1)), nil
+	}
+
+	once.DebounceReset(time.Minute)
+
+	if _, err := once.Do(workgraph.NewWorker(), f); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for range 5 {
+		once.Reset()
+	}
+	if got, want := len(pending), 1; got != want {
+		t.Fatalf("%d debounce windows started; want %d", got, want)
+	}
+	// The resets are not effective until the window elapses, so this call
+	// should still return the original result.
+	if got, _ := once.Do(workgraph.NewWorker(), f); got != 1 {
+		t.Errorf("reset took effect before the debounce window elapsed")
+	}
+
+	pending[0]()
+	if got, _ := once.Do(workgraph.NewWorker(), f); got != 2 {
+		t.Errorf("wrong result after debounce window %d; want 2", got)
+	}
+	if got, want := calls.Load(), int32(2); got != want {
+		t.Errorf("wrong number of calls %d; want %d", got, want)
+	}
+}
+
+func TestOnce_promise(t *testing.T) {
+	var once workgraph.Once[string]
+
+	_, err := once.Promise().Await(workgraph.NewWorker())
+	if _, ok := err.(workgraph.ErrNotStarted); !ok {
+		t.Errorf("wrong error before Do %v; want %T", err, workgraph.ErrNotStarted{})
+	}
+
+	_, err = once.Do(workgraph.NewWorker(), func(w *workgraph.Worker) (string, error) {
+		return "Hello, world!", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	got, err := once.Promise().Await(workgraph.NewWorker())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "Hello, world!"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestOnce_seed(t *testing.T) {
+	var source workgraph.Once[string]
+	_, err := source.Do(workgraph.NewWorker(), func(w *workgraph.Worker) (string, error) {
+		return "Hello, world!", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	value, err, ok := source.ResolvedValue()
+	if !ok {
+		t.Fatal("source Once has no resolved value")
+	}
+
+	var once workgraph.Once[string]
+	if _, _, ok := once.ResolvedValue(); ok {
+		t.Fatal("unstarted Once reports a resolved value")
+	}
+	once.Seed(value, err)
+	once.Seed("ignored", nil) // Once already has a value, so this does nothing
+
+	got, err := once.Do(workgraph.NewWorker(), func(w *workgraph.Worker) (string, error) {
+		t.Error("function called for seeded Once")
+		return "", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "Hello, world!"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestOnce_doContext(t *testing.T) {
+	var once workgraph.Once[string]
+	release := make(chan struct{})
+	f := func(w *workgraph.Worker) (string, error) {
+		<-release
+		return "Hello, world!", nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := once.DoContext(ctx, workgraph.NewWorker(), f)
+	if err != context.Canceled {
+		t.Fatalf("wrong error %v; want %v", err, context.Canceled)
+	}
+
+	// The function continues running despite the first caller giving up,
+	// so a subsequent caller can still get its result.
+	close(release)
+	got, err := once.DoContext(context.Background(), workgraph.NewWorker(), f)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "Hello, world!"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestOnce_doWorkerContext(t *testing.T) {
+	var once workgraph.Once[string]
+	release := make(chan struct{})
+	defer close(release)
+	f := func(w *workgraph.Worker) (string, error) {
+		<-release
+		return "Hello, world!", nil
+	}
+
+	// Do honors the context of a worker created by NewWorkerContext.
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err := once.Do(workgraph.NewWorkerContext(ctx), f)
+	if err != context.Canceled {
+		t.Fatalf("wrong error %v; want %v", err, context.Canceled)
+	}
+}
+
+func TestOnceValue(t *testing.T) {
+	var once workgraph.OnceValue[string]
+	var calls atomic.Int32
+	f := func(w *workgraph.Worker) string {
+		calls.Add(1)
+		return "hello"
+	}
+
+	for range 2 {
+		if got, want := once.Do(workgraph.NewWorker(), f), "hello"; got != want {
+			t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+		}
+	}
+	if got, want := calls.Load(), int32(1); got != want {
+		t.Errorf("function called %d times; want %d", got, want)
+	}
+	if err := once.Err(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestOnceValue_selfDependencyDirect(t *testing.T) {
+	var once workgraph.OnceValue[string]
+	got := once.Do(workgraph.NewWorker(), func(w *workgraph.Worker) string {
+		return once.Do(w, func(w *workgraph.Worker) string {
+			panic("inner function was called")
+		}) + "!"
+	})
+	if got != "" {
+		t.Errorf("unexpected result %q; want zero value", got)
+	}
+	selfDepErr, ok := once.Err().(workgraph.ErrSelfDependency)
+	if !ok {
+		t.Fatalf("wrong error type %T; want %T", once.Err(), selfDepErr)
+	}
+	wantRequestIDs := []workgraph.RequestID{once.RequestID()}
+	if diff := cmp.Diff(wantRequestIDs, selfDepErr.RequestIDs); diff != "" {
+		t.Error("wrong request ids\n" + diff)
+	}
+}
+
+func TestOnce_resetInFlight(t *testing.T) {
+	var once workgraph.Once[int]
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	first := make(chan int)
+	go func() {
+		got, _ := once.Do(workgraph.NewWorker(), func(w *workgraph.Worker) (int, error) {
+			close(started)
+			<-release
+			return 1, nil
+		})
+		first <- got
+	}()
+	<-started
+
+	// Resetting while the first computation is in flight starts a new
+	// computation for the next caller without disturbing the first one.
+	once.Reset()
+	got, err := once.Do(workgraph.NewWorker(), func(w *workgraph.Worker) (int, error) {
+		return 2, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := 2; got != want {
+		t.Errorf("wrong second result %d; want %d", got, want)
+	}
+
+	close(release)
+	if got, want := <-first, 1; got != want {
+		t.Errorf("wrong first result %d; want %d", got, want)
+	}
+}
+
+func TestOnce_started(t *testing.T) {
+	var once workgraph.Once[int]
+	if once.Started() {
+		t.Error("new Once reports started")
+	}
+	once.Do(workgraph.NewWorker(), func(w *workgraph.Worker) (int, error) {
+		return 1, nil
+	})
+	if !once.Started() {
+		t.Error("Once does not report started after Do")
+	}
+	once.Reset()
+	if once.Started() {
+		t.Error("Once reports started after Reset")
+	}
+}
+
+func TestOnceFunc_panic(t *testing.T) {
+	getResult := workgraph.OnceFunc(func(w *workgraph.Worker) (string, error) {
+		panic("oh no")
+	})
+
+	for range 2 {
+		_, err := getResult(workgraph.NewWorker())
+		panicErr, ok := err.(workgraph.ErrPanic)
+		if !ok {
+			t.Fatalf("wrong error type %T; want %T", err, panicErr)
+		}
+		if got, want := panicErr.Value, any("oh no"); got != want {
+			t.Errorf("wrong panic value %#v; want %#v", got, want)
+		}
+	}
+}
+
+func TestOnce_doTimeout(t *testing.T) {
+	var once workgraph.Once[string]
+	otherWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](otherWorker)
+	defer resolver.ReportSuccess(otherWorker, "too late")
+
+	awaitErr := make(chan error, 1)
+	f := func(w *workgraph.Worker) (string, error) {
+		// This request won't be resolved until the test is over, so
+		// this await can only end due to the timeout.
+		ret, err := promise.Await(w)
+		awaitErr <- err
+		return ret, err
+	}
+
+	_, err := once.DoTimeout(workgraph.NewWorker(), 10*time.Millisecond, f)
+	timeoutErr, ok := err.(workgraph.ErrTimeout)
+	if !ok {
+		t.Fatalf("wrong error %v; want %T", err, timeoutErr)
+	}
+	if got, want := timeoutErr.RequestID, once.RequestID(); !got.Equal(want) {
+		t.Errorf("wrong request ID %s; want %s", got, want)
+	}
+
+	// The function's worker context was cancelled along with the timeout,
+	// so its own await returns early too.
+	if got, want := <-awaitErr, context.DeadlineExceeded; got != want {
+		t.Errorf("wrong error from await inside f %v; want %v", got, want)
+	}
+
+	// Subsequent callers share the same timed-out result.
+	_, err = once.Do(workgraph.NewWorker(), f)
+	if _, ok := err.(workgraph.ErrTimeout); !ok {
+		t.Errorf("wrong error from second call %v; want %T", err, timeoutErr)
+	}
+}
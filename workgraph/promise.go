@@ -1,34 +1,381 @@
 package workgraph
 
 import (
+	"context"
 	"fmt"
+	"time"
 )
 
 // Promise is a handle through which many different workers can wait
 // for the result of a request to become available.
+//
+// A Promise is a small value that can be freely copied, and all copies
+// refer to the same request. Use [Promise.Clone] to make it explicit that
+// a copy is intended to be shared with other workers.
 type Promise[T any] struct {
 	inner *requestInner
 }
 
+// Clone returns another promise for the same request as the receiver.
+//
+// This is cheap, and the result is interchangeable with the original. Any
+// number of workers can each hold a clone of the same promise and await it
+// independently, and they all observe the same result.
+func (rc Promise[T]) Clone() Promise[T] {
+	return Promise[T]{inner: rc.inner}
+}
+
 // Await blocks until the associated request has been resolved, or until
 // a problem forces it to resolve with a usage error to avoid deadlocking.
+//
+// The requesting worker may be nil to represent a "root" waiter, such as the
+// main goroutine collecting the final result of some work, which is not a
+// worker and so can't be responsible for any requests. A nil worker cannot
+// participate in a self-dependency cycle, and so awaiting with a nil worker
+// skips self-dependency detection. Only goroutines that will never be
+// responsible for any requests may use a nil worker.
+//
+// Each worker can await only one promise at a time, and so Await panics if
+// the worker is already awaiting a different promise on another goroutine.
+// Awaiting the same promise that the worker is already awaiting is allowed,
+// and just waits for the same result. Each such call counts as a separate
+// await for the worker's await budget, callback, and observer, and the
+// worker remains awaiting the promise until all of the calls have returned.
+//
+// If the worker was created by [NewWorkerContext] then Await also returns
+// early if that context is cancelled, as with [Promise.AwaitContext].
 func (rc Promise[T]) Await(requestingWorker *Worker) (T, error) {
-	if waitingFor := requestingWorker.inner.awaiting.Load(); waitingFor != nil {
+	return rc.AwaitContext(workerContext(requestingWorker), requestingWorker)
+}
+
+// AwaitContext is like [Promise.Await] except that it also returns early if
+// the given context is cancelled before the request is resolved, in which
+// case the error is the one returned by the context's Err method.
+//
+// The given context is used instead of any context associated with the
+// worker by [NewWorkerContext]. Callers that want to respect both can derive
+// the given context from the one returned by [Worker.Context].
+//
+// Cancellation affects only this particular call. The request itself remains
+// unresolved, so other workers awaiting the same promise are unaffected and
+// the requesting worker may await the same promise again later.
+//
+// If the promise was created by [NewLazyRequest] and the requesting worker
+// is the first to await it then the lazy function runs inline as usual, and
+// the context cannot interrupt it.
+func (rc Promise[T]) AwaitContext(ctx context.Context, requestingWorker *Worker) (T, error) {
+	if requestingWorker == nil {
+		return rc.awaitRoot(ctx)
+	}
+	if waitingFor := requestingWorker.inner.awaiting.Load(); waitingFor != nil && waitingFor != rc.inner {
 		// Each worker can be awaiting only one promise at a time, so this
-		// is always a bug in the caller.
-		panic(fmt.Sprintf("worker %p awaits multiple promises", requestingWorker.inner))
+		// is always a bug in the caller. Awaiting the same promise again
+		// is allowed, though, and just joins the existing wait.
+		panic(requestingWorker.inner.errConcurrentAwait(rc.inner))
 	}
 	if result := rc.inner.result.Load(); result != nil {
 		// If the request was already resolved then we'll return as quickly
 		// as possible to minimize overhead.
 		return resultRet[T](result)
 	}
+	if err := ctx.Err(); err != nil {
+		var zero T
+		return zero, err
+	}
+	if run := rc.inner.claimLazy(requestingWorker); run != nil {
+		// This is a lazy request and we're the first to await it, so
+		// we're now responsible for producing its result inline. If the
+		// function awaits this same promise then that'll be detected as
+		// a self-dependency because we're now the responsible worker.
+		run(requestingWorker)
+		if result := rc.inner.result.Load(); result != nil {
+			return resultRet[T](result)
+		}
+	}
 
 	// If we get here then we need to do the slow-path await.
-	result := rc.inner.await(requestingWorker)
+	result, err := rc.inner.await(ctx, requestingWorker)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return resultRet[T](result)
+}
+
+// AwaitTimeout is like [Promise.Await] except that it gives up waiting once
+// the given duration has elapsed, returning [ErrTimeout].
+//
+// This is a safety net for situations that self-dependency detection cannot
+// see, such as a responsible worker that is blocked on something outside of
+// the workgraph that never completes. As with [Promise.AwaitContext], giving
+// up affects only this particular call and the request itself remains
+// unresolved.
+func (rc Promise[T]) AwaitTimeout(requestingWorker *Worker, timeout time.Duration) (T, error) {
+	parent := workerContext(requestingWorker)
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+	ret, err := rc.AwaitContext(ctx, requestingWorker)
+	if err != nil && err == ctx.Err() && parent.Err() == nil {
+		// The error might have come from the request itself, or the request
+		// might have been resolved just as we gave up, in which case we
+		// prefer to return its result.
+		if result := rc.inner.result.Load(); result != nil {
+			return resultRet[T](result)
+		}
+		var zero T
+		return zero, ErrTimeout{RequestID: rc.inner.RequestID()}
+	}
+	return ret, err
+}
+
+// AwaitDetailed is like [Promise.Await] except that it also reports how the
+// request was resolved, so that callers can distinguish an error reported
+// by the responsible worker from one reported by this library to describe
+// a problem with the graph of workers and requests.
+//
+// If Await returns early without the request being resolved, such as
+// because the worker's context was cancelled, then the kind is
+// [ResolutionNone].
+func (rc Promise[T]) AwaitDetailed(requestingWorker *Worker) (T, error, ResolutionKind) {
+	v, err := rc.Await(requestingWorker)
+	result := rc.inner.result.Load()
+	if result == nil {
+		return v, err, ResolutionNone
+	}
+	// The request might have been resolved just after Await gave up, in
+	// which case we prefer to return the result.
+	v, err = resultRet[T](result)
+	return v, err, result.Kind()
+}
+
+// ResolutionKind describes how a request was resolved, as reported by
+// [Promise.AwaitDetailed].
+type ResolutionKind int
+
+const (
+	// ResolutionNone means that the request was not resolved.
+	ResolutionNone ResolutionKind = iota
+
+	// ResolutionExplicit means that the request was resolved by a worker
+	// reporting a result, which might include an error.
+	ResolutionExplicit
+
+	// ResolutionSelfDependency means that this library resolved the request
+	// with [ErrSelfDependency].
+	ResolutionSelfDependency
+
+	// ResolutionUnresolved means that this library resolved the request with
+	// [ErrUnresolved] because the responsible worker was dropped.
+	ResolutionUnresolved
+
+	// ResolutionUsageFault means that this library resolved the request
+	// with some other error, such as [ErrAbandoned] or [ErrTimeout].
+	ResolutionUsageFault
+)
+
+func (k ResolutionKind) String() string {
+	switch k {
+	case ResolutionNone:
+		return "none"
+	case ResolutionExplicit:
+		return "explicit"
+	case ResolutionSelfDependency:
+		return "self-dependency"
+	case ResolutionUnresolved:
+		return "unresolved"
+	case ResolutionUsageFault:
+		return "usage fault"
+	default:
+		return fmt.Sprintf("ResolutionKind(%d)", int(k))
+	}
+}
+
+// Done returns a channel that is closed once the associated request has been
+// resolved, for use in select statements alongside other channels.
+//
+// Receiving from the channel does not count as awaiting the promise, and so
+// it does not participate in self-dependency detection. Use [Promise.Await]
+// or [Promise.TryAwait] to obtain the result once the channel is closed.
+//
+// Waiting for this channel does not cause a promise created by
+// [NewLazyRequest] to begin computing its result.
+func (rc Promise[T]) Done() <-chan struct{} {
+	return rc.inner.doneChan()
+}
+
+// OnResolve registers a function to be called exactly once with the result
+// of the request once it's resolved, without needing a worker to await it.
+//
+// If the request is already resolved then f is called immediately, before
+// OnResolve returns. Otherwise f is called later on whichever goroutine
+// resolves the request, which could be a goroutine belonging to the
+// responsible worker or, if the responsible worker is dropped, a goroutine
+// used by the garbage collector. The function is called without holding any
+// of this package's locks, but it should return quickly and must not block
+// waiting for anything that the resolving goroutine might be responsible
+// for.
+//
+// As with [Promise.Done], registering a function does not cause a promise
+// created by [NewLazyRequest] to begin computing its result.
+func (rc Promise[T]) OnResolve(f func(T, error)) {
+	callback := func(result *requestResult) {
+		value, err := resultRet[T](result)
+		f(value, err)
+	}
+	if !rc.inner.addOnResolve(callback) {
+		callback(rc.inner.result.Load())
+	}
+}
+
+// WaiterCount returns the number of goroutines that are currently blocked
+// awaiting the associated request.
+//
+// This is intended for diagnostics and heuristics such as backpressure. The
+// count can change at any time, so the result might already be outdated
+// by the time it's returned.
+func (rc Promise[T]) WaiterCount() int {
+	return int(rc.inner.waiters.Load())
+}
+
+// IsResolved returns true if the associated request has been resolved, either
+// explicitly by a worker or by this library to report a usage fault.
+//
+// This does not need a worker and never blocks.
+func (rc Promise[T]) IsResolved() bool {
+	return rc.inner.result.Load() != nil
+}
+
+// TryAwait returns the result of the associated request if it has already
+// been resolved, without blocking.
+//
+// The final return value is false if the request is not yet resolved, in
+// which case the other return values are meaningless. TryAwait never blocks
+// and so doesn't need a worker and never checks for self-dependency.
+func (rc Promise[T]) TryAwait() (T, error, bool) {
+	result := rc.inner.result.Load()
+	if result == nil {
+		var zero T
+		return zero, nil, false
+	}
+	value, err := resultRet[T](result)
+	return value, err, true
+}
+
+// awaitRoot is the variant of [Promise.AwaitContext] for a nil requesting
+// worker.
+func (rc Promise[T]) awaitRoot(ctx context.Context) (T, error) {
+	if rc.inner.lazy.Load() != nil {
+		// Since the caller has no worker of its own, a lazy function gets
+		// a new worker to run on instead.
+		w := NewWorker()
+		if run := rc.inner.claimLazy(w); run != nil {
+			run(w)
+		}
+	}
+	result, err := rc.inner.await(ctx, nil)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
 	return resultRet[T](result)
 }
 
+// AwaitOrCompute is a variant of [Promise.Await] which, if the request is
+// not yet resolved and the requesting worker is the one responsible for
+// resolving it, calls f inline on that worker and resolves the request with
+// its result before returning that result.
+//
+// If some other worker is responsible for the request then this behaves
+// just like [Promise.Await], and f is not called. Therefore any number of
+// workers can call this method for the same promise and only the responsible
+// worker will compute the result, while the others wait for it.
+//
+// If the promise was created by [NewLazyRequest] and has not yet been
+// awaited then the requesting worker claims responsibility for it as usual,
+// but the lazy request's own function is used to produce the result, rather
+// than f.
+func (rc Promise[T]) AwaitOrCompute(requestingWorker *Worker, f func(*Worker) (T, error)) (T, error) {
+	if requestingWorker == nil {
+		return rc.Await(requestingWorker)
+	}
+	// A lazy request's creator is already responsible for it, so we must
+	// give the lazy function a chance to claim the request before deciding
+	// whether to call f, or else the lazy function would never run.
+	if run := rc.inner.claimLazy(requestingWorker); run != nil {
+		run(requestingWorker)
+		return rc.Await(requestingWorker)
+	}
+	if result := rc.inner.result.Load(); result == nil && rc.inner.responsible.Load() == requestingWorker.inner {
+		ret, err := f(requestingWorker)
+		rc.inner.resolveExplicit(requestingWorker, ret, err)
+	}
+	return rc.Await(requestingWorker)
+}
+
+// ResponsibleAlive returns true if the worker currently responsible for
+// resolving the request has not yet been dropped.
+//
+// If this returns false for a request that is not yet resolved then the
+// request has either already failed with [ErrUnresolved] or will do so
+// imminently, and so a caller might choose to avoid awaiting it.
+//
+// A worker is considered dropped once the garbage collector has noticed that
+// it's unreachable, or once it has been explicitly dropped by a mechanism
+// such as returning from [WithWorkerContext], so this can potentially return
+// true for some time after the worker's goroutine has exited.
+func (rc Promise[T]) ResponsibleAlive() bool {
+	worker := rc.inner.responsible.Load()
+	if worker == nil {
+		// Requests that were settled on creation never had a responsible
+		// worker at all.
+		return false
+	}
+	worker.mu.Lock()
+	defer worker.mu.Unlock()
+	return !worker.dropped
+}
+
+// ResolvedBy returns the identifier of the worker that resolved the request,
+// which could be either the worker that was originally responsible for it or
+// some other worker that responsibility was later delegated to.
+//
+// The second return value is false if the request is not yet resolved, or
+// if it was resolved by this library to report a usage fault, such as
+// [ErrSelfDependency] or [ErrUnresolved], rather than by a worker.
+func (rc Promise[T]) ResolvedBy() (WorkerID, bool) {
+	return rc.inner.resolvedBy()
+}
+
+// WouldSelfDepend returns true if the given worker awaiting the given promise
+// right now would create a self-dependency cycle, and so would fail with
+// [ErrSelfDependency].
+//
+// This performs the same check that [Promise.Await] performs before blocking,
+// but without registering the worker as awaiting the promise and without
+// resolving any requests. The answer can become outdated as soon as it's
+// returned if other workers are concurrently awaiting or delegating
+// requests, so this is suitable only as a hint, such as for a scheduler
+// deciding what to await next, or for testing.
+//
+// This always returns false for a promise that's already resolved, because
+// awaiting it would not block.
+func WouldSelfDepend[T any](w *Worker, p Promise[T]) bool {
+	if w == nil || p.inner.result.Load() != nil {
+		return false
+	}
+	selfDependency, _ := detectSelfDependency(p.inner, w.inner, false)
+	return selfDependency
+}
+
+// workerContext returns the context that [Promise.Await] should use for the
+// given worker, which may be nil.
+func workerContext(w *Worker) context.Context {
+	if w == nil {
+		return context.Background()
+	}
+	return w.Context()
+}
+
 func (rc Promise[T]) isNil() bool {
 	return rc.inner == nil
 }
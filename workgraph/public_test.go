@@ -1,9 +1,13 @@
 package workgraph_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"runtime"
 	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -59,8 +63,8 @@ func TestSelfDependencyDirect(t *testing.T) {
 	if !ok {
 		t.Fatalf("wrong error type %T; want %T", err, selfDepErr)
 	}
-	wantResultIDs := []workgraph.RequestID{resolver.RequestID()}
-	if diff := cmp.Diff(wantResultIDs, selfDepErr.RequestIDs); diff != "" {
+	wantRequestIDs := []workgraph.RequestID{resolver.RequestID()}
+	if diff := cmp.Diff(wantRequestIDs, selfDepErr.RequestIDs); diff != "" {
 		t.Error("wrong request ids\n" + diff)
 	}
 }
@@ -90,16 +94,16 @@ func TestSelfDependencyIndirect(t *testing.T) {
 	}
 	t.Logf("affected request ids: %#v", selfDepErr.RequestIDs)
 
-	// The reported ResultIDs are not guaranteed to be any particular order
+	// The reported RequestIDs are not guaranteed to be any particular order
 	// but we expect both to be present.
 	if got, want := len(selfDepErr.RequestIDs), 2; got != want {
 		t.Fatalf("wrong number of failed request ids %d; want %d", got, want)
 	}
 	if !slices.Contains(selfDepErr.RequestIDs, resolver1.RequestID()) {
-		t.Errorf("resolver1's ResultID is not mentioned in the error")
+		t.Errorf("resolver1's RequestID is not mentioned in the error")
 	}
 	if !slices.Contains(selfDepErr.RequestIDs, resolver2.RequestID()) {
-		t.Errorf("resolver2's ResultID is not mentioned in the error")
+		t.Errorf("resolver2's RequestID is not mentioned in the error")
 	}
 }
 
@@ -135,3 +139,632 @@ func TestUnresolved(t *testing.T) {
 		t.Errorf("error has the wrong RequestID")
 	}
 }
+
+func TestUnresolved_close(t *testing.T) {
+	// This is a deterministic version of TestUnresolved, which closes the
+	// responsible worker explicitly instead of waiting for the garbage
+	// collector to notice that it was dropped.
+	mainWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		w.Close()
+		w.Close() // closing again has no additional effect
+	}, resolver)
+
+	value, err := promise.Await(mainWorker)
+	if err == nil {
+		t.Fatalf("unexpected success with value %#v; want unresolved error", value)
+	}
+	unresolvedErr, ok := err.(workgraph.ErrUnresolved)
+	if !ok {
+		t.Fatalf("wrong error type %T; want %T", err, unresolvedErr)
+	}
+	if unresolvedErr.RequestID != resolver.RequestID() {
+		t.Errorf("error has the wrong RequestID")
+	}
+}
+
+func TestLazyRequest(t *testing.T) {
+	defer runtime.GC()
+
+	mainWorker := workgraph.NewWorker()
+	var calls int
+	promise := workgraph.NewLazyRequest(mainWorker, func(w *workgraph.Worker) (string, error) {
+		calls++
+		return "Hello", nil
+	})
+	if calls != 0 {
+		t.Fatalf("lazy function called before the promise was awaited")
+	}
+
+	for i := range 2 {
+		got, err := promise.Await(mainWorker)
+		if err != nil {
+			t.Errorf("unexpected error from await %d: %s", i, err)
+		}
+		if want := "Hello"; got != want {
+			t.Errorf("wrong result from await %d\ngot:  %s\nwant: %s", i, got, want)
+		}
+	}
+	if got, want := calls, 1; got != want {
+		t.Errorf("wrong number of calls %d; want %d", got, want)
+	}
+}
+
+func TestLazyRequest_selfDependency(t *testing.T) {
+	defer runtime.GC()
+
+	mainWorker := workgraph.NewWorker()
+	var promise workgraph.Promise[string]
+	promise = workgraph.NewLazyRequest(mainWorker, func(w *workgraph.Worker) (string, error) {
+		return promise.Await(w)
+	})
+
+	value, err := promise.Await(mainWorker)
+	if err == nil {
+		t.Fatalf("unexpected success with value %#v; want self-dependency error", value)
+	}
+	selfDepErr, ok := err.(workgraph.ErrSelfDependency)
+	if !ok {
+		t.Fatalf("wrong error type %T; want %T", err, selfDepErr)
+	}
+	if got, want := len(selfDepErr.RequestIDs), 1; got != want {
+		t.Errorf("wrong number of request ids %d; want %d", got, want)
+	}
+}
+
+func TestResolvedBy(t *testing.T) {
+	defer runtime.GC()
+
+	mainWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+	if _, ok := promise.ResolvedBy(); ok {
+		t.Fatal("unresolved promise reports a resolving worker")
+	}
+
+	delegateID := make(chan workgraph.WorkerID, 1)
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		delegateID <- w.ID()
+		resolver.ReportSuccess(w, "Hello")
+	}, resolver)
+
+	if _, err := promise.Await(mainWorker); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	got, ok := promise.ResolvedBy()
+	if !ok {
+		t.Fatal("resolved promise does not report a resolving worker")
+	}
+	if want := <-delegateID; got != want {
+		t.Errorf("wrong resolving worker %s; want %s", got, want)
+	}
+	if got == mainWorker.ID() {
+		t.Errorf("promise reports that the original worker resolved it")
+	}
+	if fromResolver, _ := resolver.ResolvedBy(); fromResolver != got {
+		t.Errorf("resolver and promise disagree about which worker resolved the request")
+	}
+}
+
+func TestAwaitOrCompute(t *testing.T) {
+	defer runtime.GC()
+
+	var calls atomic.Int32
+	compute := func(w *workgraph.Worker) (string, error) {
+		calls.Add(1)
+		return "Hello", nil
+	}
+
+	mainWorker := workgraph.NewWorker()
+	_, promise := workgraph.NewRequest[string](mainWorker)
+	results := make(chan string, 1)
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		// This worker is not responsible for the request and so it
+		// must wait for the main worker to compute the result.
+		result, err := promise.AwaitOrCompute(w, compute)
+		if err != nil {
+			t.Errorf("unexpected error in second awaiter: %s", err)
+		}
+		results <- result
+	})
+
+	got, err := promise.AwaitOrCompute(mainWorker, compute)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "Hello"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+	if got, want := <-results, "Hello"; got != want {
+		t.Errorf("wrong result for second awaiter\ngot:  %s\nwant: %s", got, want)
+	}
+	if got, want := calls.Load(), int32(1); got != want {
+		t.Errorf("wrong number of calls %d; want %d", got, want)
+	}
+}
+
+func TestAwaitOrCompute_lazy(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	promise := workgraph.NewLazyRequest(mainWorker, func(w *workgraph.Worker) (string, error) {
+		return "lazy", nil
+	})
+
+	// mainWorker created the lazy request and so is already responsible for
+	// it, but the lazy function still takes priority over f.
+	got, err := promise.AwaitOrCompute(mainWorker, func(w *workgraph.Worker) (string, error) {
+		return "f", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "lazy"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestResponsibleAlive(t *testing.T) {
+	var promise workgraph.Promise[string]
+	workgraph.WithWorkerContext(context.Background(), func(w *workgraph.Worker) {
+		_, promise = workgraph.NewRequest[string](w)
+		if !promise.ResponsibleAlive() {
+			t.Error("responsible worker is not alive while still in use")
+		}
+	})
+	if promise.ResponsibleAlive() {
+		t.Error("responsible worker is still alive after being dropped")
+	}
+}
+
+func TestAwaitContext(t *testing.T) {
+	defer runtime.GC()
+
+	mainWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+	otherResolver, otherPromise := workgraph.NewRequest[string](mainWorker)
+	ctx, cancel := context.WithCancel(context.Background())
+	mainWorker.SetOnBlock(func(workgraph.RequestID) {
+		// Once we're blocked we'll cancel the context, which should
+		// cause the await to return early.
+		cancel()
+	})
+
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		// This worker never resolves the first request until after the
+		// main worker has given up waiting for it.
+		otherPromise.Await(w)
+		resolver.ReportSuccess(w, "too late")
+	}, resolver)
+
+	_, err := promise.AwaitContext(ctx, mainWorker)
+	if err != context.Canceled {
+		t.Fatalf("wrong error %v; want %v", err, context.Canceled)
+	}
+
+	// The worker must be able to await something else after cancellation.
+	mainWorker.SetOnBlock(nil)
+	otherResolver.ReportSuccess(mainWorker, "unblocked")
+	got, err := promise.Await(mainWorker)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "too late"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestAwaitContext_alreadyCancelled(t *testing.T) {
+	defer runtime.GC()
+
+	mainWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Awaiting our own request would normally be a self-dependency, but
+	// with an already-cancelled context we should return before even
+	// registering the await, and so the request remains unresolved.
+	_, err := promise.AwaitContext(ctx, mainWorker)
+	if err != context.Canceled {
+		t.Fatalf("wrong error %v; want %v", err, context.Canceled)
+	}
+	resolver.ReportSuccess(mainWorker, "Hello")
+	got, err := promise.Await(mainWorker)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "Hello"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestAwaitNilWorker(t *testing.T) {
+	defer runtime.GC()
+
+	mainWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		resolver.ReportSuccess(w, "Hello")
+	}, resolver)
+	runtime.KeepAlive(mainWorker)
+
+	got, err := promise.Await(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "Hello"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestTryAwait(t *testing.T) {
+	defer runtime.GC()
+
+	mainWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+	if _, _, ok := promise.TryAwait(); ok {
+		t.Fatal("TryAwait succeeded for unresolved request")
+	}
+	if promise.IsResolved() {
+		t.Fatal("unresolved request reports that it is resolved")
+	}
+
+	resolver.ReportSuccess(mainWorker, "Hello")
+	if !promise.IsResolved() {
+		t.Fatal("resolved request reports that it is not resolved")
+	}
+	got, err, ok := promise.TryAwait()
+	if !ok {
+		t.Fatal("TryAwait failed for resolved request")
+	}
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "Hello"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestPromiseDone(t *testing.T) {
+	defer runtime.GC()
+
+	mainWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+	done := promise.Done()
+	select {
+	case <-done:
+		t.Fatal("Done channel closed before request was resolved")
+	default:
+	}
+
+	resolver.ReportSuccess(mainWorker, "Hello")
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Done channel not closed after request was resolved")
+	}
+	select {
+	case <-promise.Done():
+	default:
+		t.Fatal("Done channel for already-resolved request is not closed")
+	}
+}
+
+func TestPromiseOnResolve(t *testing.T) {
+	defer runtime.GC()
+
+	mainWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+	var calls []string
+	promise.OnResolve(func(v string, err error) {
+		calls = append(calls, "first: "+v)
+	})
+	if len(calls) != 0 {
+		t.Fatal("callback called before request was resolved")
+	}
+
+	resolver.ReportSuccess(mainWorker, "Hello")
+	promise.OnResolve(func(v string, err error) {
+		calls = append(calls, "second: "+v)
+	})
+	want := []string{"first: Hello", "second: Hello"}
+	if diff := cmp.Diff(want, calls); diff != "" {
+		t.Error("wrong callback calls\n" + diff)
+	}
+}
+
+func TestPromiseOnResolve_usageFault(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	_, promise := workgraph.NewRequest[string](mainWorker)
+	got := make(chan error, 1)
+	promise.OnResolve(func(v string, err error) {
+		// Touching the graph from inside the callback must not deadlock.
+		_ = promise.IsResolved()
+		got <- err
+	})
+
+	mainWorker.Cancel(nil)
+	if _, ok := (<-got).(workgraph.ErrUnresolved); !ok {
+		t.Errorf("callback did not receive ErrUnresolved")
+	}
+}
+
+func TestPromiseWaiterCount(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+	if got := promise.WaiterCount(); got != 0 {
+		t.Fatalf("wrong initial waiter count %d; want 0", got)
+	}
+
+	const numWaiters = 3
+	done := make(chan struct{})
+	for range numWaiters {
+		go func() {
+			promise.Await(workgraph.NewWorker())
+			done <- struct{}{}
+		}()
+	}
+	for promise.WaiterCount() < numWaiters {
+		runtime.Gosched()
+	}
+
+	resolver.ReportSuccess(mainWorker, "Hello")
+	for range numWaiters {
+		<-done
+	}
+	if got := promise.WaiterCount(); got != 0 {
+		t.Errorf("wrong final waiter count %d; want 0", got)
+	}
+}
+
+func TestAwaitTimeout(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	otherWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](otherWorker)
+
+	_, err := promise.AwaitTimeout(mainWorker, 10*time.Millisecond)
+	timeoutErr, ok := err.(workgraph.ErrTimeout)
+	if !ok {
+		t.Fatalf("wrong error %v; want %T", err, timeoutErr)
+	}
+	if timeoutErr.RequestID != resolver.RequestID() {
+		t.Errorf("error has the wrong RequestID")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error does not match context.DeadlineExceeded")
+	}
+
+	// The worker is no longer awaiting after the timeout, so it can await
+	// the same promise again.
+	resolver.ReportSuccess(otherWorker, "Hello")
+	got, err := promise.AwaitTimeout(mainWorker, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "Hello"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestAwaitSamePromiseTwice(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	otherWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](otherWorker)
+
+	blocked := make(chan struct{}, 2)
+	mainWorker.SetOnBlock(func(workgraph.RequestID) {
+		blocked <- struct{}{}
+	})
+	first := make(chan string)
+	go func() {
+		got, _ := promise.Await(mainWorker)
+		first <- got
+	}()
+	<-blocked
+
+	// mainWorker is already awaiting this promise on the other goroutine,
+	// so awaiting it again just joins that wait.
+	go resolver.ReportSuccess(otherWorker, "Hello")
+	got, err := promise.Await(mainWorker)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "Hello"; got != want {
+		t.Errorf("wrong second result\ngot:  %s\nwant: %s", got, want)
+	}
+	if got, want := <-first, "Hello"; got != want {
+		t.Errorf("wrong first result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestAwaitSamePromiseTwice_firstCancelled(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	otherWorker := workgraph.NewWorker()
+	_, promiseP := workgraph.NewRequest[string](otherWorker)
+	_, promiseQ := workgraph.NewRequest[string](mainWorker)
+
+	blocked := make(chan struct{}, 2)
+	mainWorker.SetOnBlock(func(workgraph.RequestID) {
+		blocked <- struct{}{}
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := promiseP.AwaitContext(ctx, mainWorker)
+		first <- err
+	}()
+	<-blocked
+	second := make(chan error)
+	go func() {
+		_, err := promiseP.Await(mainWorker)
+		second <- err
+	}()
+	<-blocked
+
+	// The first waiter leaves early, but mainWorker is still awaiting P on
+	// the second goroutine...
+	cancel()
+	if err := <-first; err != context.Canceled {
+		t.Fatalf("wrong error from first waiter %v; want %v", err, context.Canceled)
+	}
+
+	// ...and so otherWorker awaiting Q, which mainWorker is responsible
+	// for, is a self-dependency rather than a deadlock.
+	_, err := promiseQ.AwaitTimeout(otherWorker, 5*time.Second)
+	if !errors.Is(err, workgraph.ErrSelfDependency{}) {
+		t.Errorf("wrong error %v; want self-dependency error", err)
+	}
+	select {
+	case err := <-second:
+		if !errors.Is(err, workgraph.ErrSelfDependency{}) {
+			t.Errorf("wrong error from second waiter %v; want self-dependency error", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("second waiter is still blocked")
+	}
+}
+
+func TestAwaitConcurrentPanic(t *testing.T) {
+	mainWorker := workgraph.NewNamedWorker("main")
+	otherWorker := workgraph.NewWorker()
+	resolverA, promiseA := workgraph.NewRequest[string](otherWorker)
+	resolverB, promiseB := workgraph.NewRequest[string](otherWorker)
+	defer resolverB.ReportSuccess(otherWorker, "")
+
+	blocked := make(chan struct{})
+	mainWorker.SetOnBlock(func(workgraph.RequestID) {
+		close(blocked)
+	})
+	done := make(chan struct{})
+	go func() {
+		promiseA.Await(mainWorker)
+		close(done)
+	}()
+	<-blocked
+	defer func() {
+		resolverA.ReportSuccess(otherWorker, "")
+		<-done
+	}()
+
+	var recovered any
+	func() {
+		defer func() {
+			recovered = recover()
+		}()
+		promiseB.Await(mainWorker)
+	}()
+	got, ok := recovered.(workgraph.ErrConcurrentAwait)
+	if !ok {
+		t.Fatalf("wrong panic value %#v; want %T", recovered, got)
+	}
+	if !got.Awaiting.Equal(resolverA.RequestID()) {
+		t.Errorf("wrong Awaiting %s; want %s", got.Awaiting, resolverA.RequestID())
+	}
+	if !got.Requested.Equal(resolverB.RequestID()) {
+		t.Errorf("wrong Requested %s; want %s", got.Requested, resolverB.RequestID())
+	}
+	if !strings.Contains(got.Error(), `"main"`) || !strings.HasSuffix(got.Error(), "awaits multiple promises") {
+		t.Errorf("wrong message %q", got.Error())
+	}
+}
+
+func TestPromiseClone(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+
+	const waiters = 3
+	results := make(chan string, waiters)
+	for range waiters {
+		workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+			got, _ := promise.Clone().Await(w)
+			results <- got
+		})
+	}
+	resolver.ReportSuccess(mainWorker, "Hello")
+	for range waiters {
+		if got, want := <-results, "Hello"; got != want {
+			t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+		}
+	}
+	if !promise.Clone().IsResolved() {
+		t.Error("clone of resolved promise is not resolved")
+	}
+}
+
+func TestWouldSelfDepend(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	otherWorker := workgraph.NewWorker()
+	selfResolver, selfPromise := workgraph.NewRequest[string](mainWorker)
+	otherResolver, otherPromise := workgraph.NewRequest[string](otherWorker)
+
+	if !workgraph.WouldSelfDepend(mainWorker, selfPromise) {
+		t.Error("awaiting own request not reported as self-dependency")
+	}
+	if workgraph.WouldSelfDepend(mainWorker, otherPromise) {
+		t.Error("awaiting independent request reported as self-dependency")
+	}
+
+	// Once otherWorker is blocked on mainWorker's request, mainWorker
+	// awaiting otherWorker's request would complete a cycle.
+	blocked := make(chan struct{})
+	otherWorker.SetOnBlock(func(workgraph.RequestID) {
+		close(blocked)
+	})
+	done := make(chan struct{})
+	go func() {
+		selfPromise.Await(otherWorker)
+		close(done)
+	}()
+	<-blocked
+	if !workgraph.WouldSelfDepend(mainWorker, otherPromise) {
+		t.Error("indirect self-dependency not reported")
+	}
+
+	// The check must not have disturbed either request.
+	if selfPromise.IsResolved() || otherPromise.IsResolved() {
+		t.Error("WouldSelfDepend resolved a request")
+	}
+	selfResolver.ReportSuccess(mainWorker, "Hello")
+	<-done
+	otherResolver.ReportSuccess(otherWorker, "Hello")
+	if workgraph.WouldSelfDepend(mainWorker, selfPromise) {
+		t.Error("resolved request reported as self-dependency")
+	}
+}
+
+func TestAwaitDetailed(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+
+	okResolver, okPromise := workgraph.NewRequest[string](mainWorker)
+	okResolver.ReportError(mainWorker, errors.New("business error"))
+	_, err, kind := okPromise.AwaitDetailed(mainWorker)
+	if err == nil || kind != workgraph.ResolutionExplicit {
+		t.Errorf("wrong outcome for reported error (%v, %s); want %s", err, kind, workgraph.ResolutionExplicit)
+	}
+
+	_, selfPromise := workgraph.NewRequest[string](mainWorker)
+	if _, _, kind := selfPromise.AwaitDetailed(mainWorker); kind != workgraph.ResolutionSelfDependency {
+		t.Errorf("wrong kind for self-dependency %s; want %s", kind, workgraph.ResolutionSelfDependency)
+	}
+
+	droppedWorker := workgraph.NewWorker()
+	_, droppedPromise := workgraph.NewRequest[string](droppedWorker)
+	droppedWorker.Close()
+	if _, _, kind := droppedPromise.AwaitDetailed(mainWorker); kind != workgraph.ResolutionUnresolved {
+		t.Errorf("wrong kind for dropped worker %s; want %s", kind, workgraph.ResolutionUnresolved)
+	}
+
+	abandonedResolver, abandonedPromise := workgraph.NewRequest[string](mainWorker)
+	abandonedResolver.Abandon(mainWorker)
+	if _, _, kind := abandonedPromise.AwaitDetailed(mainWorker); kind != workgraph.ResolutionUsageFault {
+		t.Errorf("wrong kind for abandoned request %s; want %s", kind, workgraph.ResolutionUsageFault)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	otherWorker := workgraph.NewWorker()
+	pendingResolver, pendingPromise := workgraph.NewRequest[string](otherWorker)
+	defer pendingResolver.ReportSuccess(otherWorker, "")
+	if _, err, kind := pendingPromise.AwaitDetailed(workgraph.NewWorkerContext(ctx)); kind != workgraph.ResolutionNone || err != context.Canceled {
+		t.Errorf("wrong outcome for cancelled await (%v, %s); want (%v, %s)", err, kind, context.Canceled, workgraph.ResolutionNone)
+	}
+}
@@ -0,0 +1,140 @@
+package workgraph
+
+import (
+	"context"
+	"sync"
+)
+
+// QuorumPolicy is the signature of a function that decides when [Quorum]
+// has collected enough results to produce its own result.
+//
+// The function is called each time one of the producers completes, with the
+// results of all of the producers that have completed so far in the order
+// they completed, and the number of producers that are still running. It
+// returns true along with the overall result once it's able to decide, or
+// false to wait for more producers to complete.
+type QuorumPolicy[T comparable] func(results []Settled[T], remaining int) (T, error, bool)
+
+// Quorum starts a separate worker for each of the given producer functions
+// and returns a promise that resolves once the given policy is satisfied by
+// the results of those producers.
+//
+// Once the policy is satisfied the context passed to any producers that are
+// still running is cancelled, and their results are ignored. If all of the
+// producers complete without the policy being satisfied then the promise
+// fails with [ErrNoQuorum].
+//
+// The given worker is used only to create the request whose promise is
+// returned, and responsibility for that request is immediately delegated to
+// the producer workers.
+func Quorum[T comparable](parent *Worker, policy QuorumPolicy[T], producers ...func(context.Context, *Worker) (T, error)) Promise[T] {
+	resolver, promise := NewRequest[T](parent)
+	if len(producers) == 0 {
+		v, err, done := policy(nil, 0)
+		if !done {
+			v, err = *new(T), ErrNoQuorum{}
+		}
+		resolver.Report(parent, v, err)
+		return promise
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &quorum[T]{
+		resolver: resolver,
+		policy:   policy,
+		cancel:   cancel,
+		running:  make(map[*workerInner]struct{}, len(producers)),
+	}
+	workers := make([]*Worker, len(producers))
+	for i := range producers {
+		if i == 0 {
+			// The first producer is initially responsible for the
+			// overall result, but it'll pass that responsibility on to
+			// another producer if it completes without satisfying the
+			// policy.
+			workers[i] = NewWorker(resolver)
+		} else {
+			workers[i] = NewWorker()
+		}
+		q.running[workers[i].inner] = struct{}{}
+	}
+	for i, f := range producers {
+		w := workers[i]
+		go func() {
+			v, err := f(ctx, w)
+			q.report(w, v, err)
+		}()
+	}
+	return promise
+}
+
+// Majority is a [QuorumPolicy] that succeeds once more than half of all of
+// the producers have succeeded with equal values, or fails with
+// [ErrNoQuorum] once that is no longer possible.
+func Majority[T comparable](results []Settled[T], remaining int) (T, error, bool) {
+	total := len(results) + remaining
+	counts := make(map[T]int)
+	best := 0
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		counts[result.Value]++
+		if counts[result.Value]*2 > total {
+			return result.Value, nil, true
+		}
+		best = max(best, counts[result.Value])
+	}
+	if (best+remaining)*2 <= total {
+		var zero T
+		return zero, ErrNoQuorum{}, true
+	}
+	var zero T
+	return zero, nil, false
+}
+
+// quorum is the shared state between the producers started by [Quorum].
+type quorum[T comparable] struct {
+	resolver Resolver[T]
+	policy   QuorumPolicy[T]
+	cancel   context.CancelFunc
+
+	mu      sync.Mutex
+	results []Settled[T]
+	running map[*workerInner]struct{}
+	done    bool
+}
+
+func (q *quorum[T]) report(w *Worker, v T, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.done {
+		return
+	}
+	q.results = append(q.results, Settled[T]{Value: v, Err: err})
+	delete(q.running, w.inner)
+
+	v, err, done := q.policy(q.results, len(q.running))
+	if !done && len(q.running) == 0 {
+		v, err, done = *new(T), ErrNoQuorum{}, true
+	}
+	if done {
+		// Whichever producer completes the quorum takes responsibility
+		// for the overall result so that it can resolve it.
+		q.done = true
+		q.resolver.inner.setResponsibleWorker(w.inner)
+		q.resolver.Report(w, v, err)
+		q.cancel()
+		return
+	}
+
+	// If we were responsible for the overall result then we must pass that
+	// responsibility to a producer that's still running before we return,
+	// or else the result would fail once our worker is dropped.
+	if q.resolver.inner.responsible.Load() == w.inner {
+		for next := range q.running {
+			q.resolver.inner.setResponsibleWorker(next)
+			break
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package workgraph_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apparentlymart/go-workgraph/workgraph"
+)
+
+func TestQuorum_majority(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	dissenterDone := make(chan struct{})
+	promise := workgraph.Quorum(mainWorker, workgraph.Majority[string],
+		func(ctx context.Context, w *workgraph.Worker) (string, error) {
+			// This producer disagrees, and finishes only once the others
+			// have reached agreement and so its context is cancelled.
+			<-ctx.Done()
+			close(dissenterDone)
+			return "goodbye", nil
+		},
+		func(ctx context.Context, w *workgraph.Worker) (string, error) {
+			return "hello", nil
+		},
+		func(ctx context.Context, w *workgraph.Worker) (string, error) {
+			return "hello", nil
+		},
+	)
+
+	got, err := promise.Await(mainWorker)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "hello"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+	<-dissenterDone
+}
+
+func TestQuorum_noMajority(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	promise := workgraph.Quorum(mainWorker, workgraph.Majority[int],
+		func(ctx context.Context, w *workgraph.Worker) (int, error) {
+			return 1, nil
+		},
+		func(ctx context.Context, w *workgraph.Worker) (int, error) {
+			return 2, nil
+		},
+	)
+
+	_, err := promise.Await(mainWorker)
+	if _, ok := err.(workgraph.ErrNoQuorum); !ok {
+		t.Errorf("wrong error %v; want %T", err, workgraph.ErrNoQuorum{})
+	}
+}
@@ -10,7 +10,22 @@ import (
 //
 // The given worker is initially responsible for resolving the request.
 func NewRequest[T any](responsibleWorker *Worker) (Resolver[T], Promise[T]) {
-	newInner := newRequestInner(responsibleWorker.inner)
+	return newRequest[T](responsibleWorker, "")
+}
+
+// NewRequestNamed is like [NewRequest] except that the new request has the
+// given name.
+//
+// The name is used only for diagnostic purposes, such as in the message of
+// an [ErrSelfDependency] error, and can be retrieved using
+// [RequestID.Label]. It does not affect the request's identity, and so
+// multiple requests may have the same name.
+func NewRequestNamed[T any](responsibleWorker *Worker, name string) (Resolver[T], Promise[T]) {
+	return newRequest[T](responsibleWorker, name)
+}
+
+func newRequest[T any](responsibleWorker *Worker, name string) (Resolver[T], Promise[T]) {
+	newInner := newRequestInner(responsibleWorker.inner, name)
 
 	resolver := Resolver[T]{
 		inner: newInner,
@@ -28,6 +43,52 @@ func NewRequest[T any](responsibleWorker *Worker) (Resolver[T], Promise[T]) {
 	return resolver, consumer
 }
 
+// NewRequests begins n new requests at once, returning their resolvers and
+// promises in corresponding order.
+//
+// This is equivalent to calling [NewRequest] n times, but allocates the
+// internal representation of all of the requests together to reduce the
+// overhead of creating many requests. In return, the memory for all of the
+// requests remains allocated until all of them are unreachable.
+//
+// The given worker is initially responsible for resolving all of the
+// requests.
+func NewRequests[T any](responsibleWorker *Worker, n int) ([]Resolver[T], []Promise[T]) {
+	inners := newRequestInners(responsibleWorker.inner, n)
+	resolvers := make([]Resolver[T], n)
+	promises := make([]Promise[T], n)
+	for i := range inners {
+		resolvers[i] = Resolver[T]{inner: &inners[i]}
+		promises[i] = Promise[T]{inner: &inners[i]}
+	}
+	runtime.KeepAlive(responsibleWorker)
+	return resolvers, promises
+}
+
+// NewLazyRequest begins a new request whose result is produced by calling f
+// inline on whichever worker first awaits the returned promise, rather than
+// on a separately-started worker.
+//
+// The first worker to await the promise becomes responsible for the request
+// and runs f, and then any other workers that await the promise concurrently
+// or afterwards receive the same result. If nothing ever awaits the promise
+// then f is never called.
+//
+// Until the promise is first awaited the given worker is responsible for
+// the request, and so the usual rules about keeping that worker live apply.
+//
+// If f directly or indirectly awaits the promise it is producing then that
+// await fails with [ErrSelfDependency].
+func NewLazyRequest[T any](responsibleWorker *Worker, f func(*Worker) (T, error)) Promise[T] {
+	resolver, promise := NewRequest[T](responsibleWorker)
+	run := func(w *Worker) {
+		ret, err := f(w)
+		resolver.Report(w, ret, err)
+	}
+	resolver.inner.lazy.Store(&run)
+	return promise
+}
+
 // ResolverContainer is implemented by types that in some sense "contain" [Resolver]
 // objects, allowing the responsibility for all of those results to be passed
 // in aggregate to a new task when calling [NewWorker].
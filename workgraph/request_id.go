@@ -2,6 +2,7 @@ package workgraph
 
 import (
 	"fmt"
+	"log/slog"
 	"weak"
 )
 
@@ -21,9 +22,15 @@ type RequestID struct {
 	// and not about the pointee itself. Internally this creates an extra
 	// indirection through a heap-allocated pointer value where the pointer
 	// to that allocation is actually what we're comparing when using a
-	// RequestID as a comparable identifier, whereas the underlying resultInner
+	// RequestID as a comparable identifier, whereas the underlying requestInner
 	// remains eligible for garbage collection.
-	ptr weak.Pointer[requestInner]
+	//
+	// Weak pointers created from different objects never compare equal, even
+	// after those objects have been collected, so the pointer alone is
+	// enough for identity. The serial number is included so that the
+	// identifier can still be described after the request is collected.
+	ptr    weak.Pointer[requestInner]
+	serial uint64
 }
 
 // Equal returns true if other is the same [RequestID] as the receiver.
@@ -35,19 +42,92 @@ func (rid RequestID) Equal(other RequestID) bool {
 	return rid == other
 }
 
+// IsZero returns true if the receiver is [NoRequest], meaning that it does
+// not identify any request.
+func (rid RequestID) IsZero() bool {
+	return rid == NoRequest
+}
+
+// Label returns the name given to [NewRequestNamed] when the request was
+// created, or an empty string if the request has no name or if it has
+// already been garbage collected.
+func (rid RequestID) Label() string {
+	inner := rid.ptr.Value()
+	if inner == nil {
+		return ""
+	}
+	return inner.name
+}
+
 // String returns a human-oriented string representation of the result ID.
 //
 // This is intended for debug messages only. Do not use the result as a unique
 // key for a [RequestID]; this type is comparable so it can act as its own
 // unique key.
+//
+// The result includes a number that's unique to the request and remains the
+// same even after the request has been garbage collected, so that each
+// identifier can still be distinguished from others in diagnostic output.
 func (rid RequestID) String() string {
-	return fmt.Sprintf("%p", rid.ptr.Value())
+	if rid.IsZero() {
+		return "no request"
+	}
+	return fmt.Sprintf("request #%d", rid.serial)
 }
 
 func (rid RequestID) GoString() string {
 	return fmt.Sprintf("workgraph.RequestID(%s)", rid.String())
 }
 
+// LogValue implements [slog.LogValuer] by returning the same string as
+// [RequestID.String], so that identifiers appear as simple strings in structured
+// logs.
+func (rid RequestID) LogValue() slog.Value {
+	return slog.StringValue(rid.String())
+}
+
+// MarshalText implements [encoding.TextMarshaler] by returning the same
+// string as [RequestID.String], so that identifiers can be included in
+// serialized debug output such as from [Snapshot].
+func (rid RequestID) MarshalText() ([]byte, error) {
+	return []byte(rid.String()), nil
+}
+
+// Err returns the error that the request with this identifier was resolved
+// with, which is nil if the request succeeded, without needing to know the
+// request's result type.
+//
+// The second return value is false if the request is not yet resolved or if
+// the request has already been garbage collected, in which case the error is
+// always nil. Use [ResolvedValueOf] to obtain the resolved value too.
+func (rid RequestID) Err() (error, bool) {
+	_, err, resolved := ResolvedValueOf(rid)
+	return err, resolved
+}
+
+// ResolvedValueOf returns the value and error that the request with the given
+// identifier was resolved with, without needing to know the request's result
+// type.
+//
+// The final return value is false if the request is not yet resolved or if
+// the request has already been garbage collected, in which case the other
+// return values are meaningless.
+//
+// If the request was resolved by this library to report a usage fault, such
+// as [ErrSelfDependency], then the returned value is nil. Otherwise the value
+// is always of the request's result type, even if that value is itself nil.
+func ResolvedValueOf(id RequestID) (any, error, bool) {
+	inner := id.ptr.Value()
+	if inner == nil {
+		return nil, nil, false
+	}
+	result := inner.result.Load()
+	if result == nil {
+		return nil, nil, false
+	}
+	return result.value, result.err, true
+}
+
 // NoRequest is the zero value of [RequestID], representing the absence of
 // any request.
 //
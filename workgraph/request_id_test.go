@@ -0,0 +1,143 @@
+package workgraph_test
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"testing"
+
+	"github.com/apparentlymart/go-workgraph/workgraph"
+)
+
+func TestResolvedValueOf(t *testing.T) {
+	w := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[*int](w)
+	id := resolver.RequestID()
+
+	if _, _, ok := workgraph.ResolvedValueOf(id); ok {
+		t.Fatal("unresolved request reports a value")
+	}
+
+	// A typed nil pointer is a valid explicit result, distinct from the
+	// untyped nil used for usage faults.
+	resolver.ReportSuccess(w, nil)
+	got, err, ok := workgraph.ResolvedValueOf(id)
+	if !ok {
+		t.Fatal("resolved request reports no value")
+	}
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if ptr, isPtr := got.(*int); !isPtr || ptr != nil {
+		t.Errorf("wrong value %#v; want (*int)(nil)", got)
+	}
+
+	// Usage faults have no value at all.
+	selfResolver, selfPromise := workgraph.NewRequest[*int](w)
+	selfPromise.Await(w)
+	got, err, ok = workgraph.ResolvedValueOf(selfResolver.RequestID())
+	if !ok {
+		t.Fatal("failed request reports no value")
+	}
+	if _, isSelfDep := err.(workgraph.ErrSelfDependency); !isSelfDep {
+		t.Errorf("wrong error %v; want %T", err, workgraph.ErrSelfDependency{})
+	}
+	if got != nil {
+		t.Errorf("wrong value %#v; want nil", got)
+	}
+
+	promise.Await(w) // keep the first request live until we're done with it
+}
+
+func TestRequestIDErr(t *testing.T) {
+	w := workgraph.NewWorker()
+	okResolver, okPromise := workgraph.NewRequest[string](w)
+	failResolver, failPromise := workgraph.NewRequest[int](w)
+
+	if _, resolved := okResolver.RequestID().Err(); resolved {
+		t.Fatal("unresolved request reports resolved")
+	}
+
+	okResolver.ReportSuccess(w, "Hello")
+	failResolver.ReportError(w, fmt.Errorf("oh no"))
+	if err, resolved := okResolver.RequestID().Err(); !resolved || err != nil {
+		t.Errorf("wrong result for successful request (%v, %t); want (nil, true)", err, resolved)
+	}
+	if err, resolved := failResolver.RequestID().Err(); !resolved || err == nil || err.Error() != "oh no" {
+		t.Errorf("wrong result for failed request (%v, %t); want (oh no, true)", err, resolved)
+	}
+	if _, resolved := workgraph.NoRequest.Err(); resolved {
+		t.Error("NoRequest reports resolved")
+	}
+
+	// keep the requests live until we're done with them
+	okPromise.Await(w)
+	failPromise.Await(w)
+}
+
+func TestRequestIDIsZero(t *testing.T) {
+	if !workgraph.NoRequest.IsZero() {
+		t.Error("NoRequest is not zero")
+	}
+	resolver, _ := workgraph.NewRequest[int](workgraph.NewWorker())
+	if resolver.RequestID().IsZero() {
+		t.Error("real request ID is zero")
+	}
+	var once workgraph.Once[int]
+	if !once.RequestID().IsZero() {
+		t.Error("unstarted Once has non-zero request ID")
+	}
+}
+
+func TestRequestIDLogValue(t *testing.T) {
+	w := workgraph.NewWorker()
+	resolver, _ := workgraph.NewRequest[int](w)
+	id := resolver.RequestID()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Info("example", "request_id", id, "worker_id", w.ID())
+
+	want := fmt.Sprintf(`{"level":"INFO","msg":"example","request_id":%q,"worker_id":%q}`+"\n", id.String(), w.ID().String())
+	if got := buf.String(); got != want {
+		t.Errorf("wrong log output\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestRequestIDString_collected(t *testing.T) {
+	newID := func() workgraph.RequestID {
+		resolver, _ := workgraph.NewRequest[int](workgraph.NewWorker())
+		return resolver.RequestID()
+	}
+	id1 := newID()
+	id2 := newID()
+	before1, before2 := id1.String(), id2.String()
+	runtime.GC()
+	runtime.GC()
+
+	// The identifiers keep their own identity and description even once
+	// the requests they refer to have been collected.
+	if id1 == id2 {
+		t.Error("different request IDs compare equal")
+	}
+	if got := id1.String(); got != before1 {
+		t.Errorf("description changed after collection\ngot:  %s\nwant: %s", got, before1)
+	}
+	if id1.String() == id2.String() {
+		t.Errorf("different request IDs have the same description %q", id1.String())
+	}
+	if before1 == before2 {
+		t.Errorf("different request IDs have the same description %q", before1)
+	}
+	if got, want := workgraph.NoRequest.String(), "no request"; got != want {
+		t.Errorf("wrong description for NoRequest %q; want %q", got, want)
+	}
+}
@@ -1,7 +1,7 @@
 package workgraph
 
 import (
-	"fmt"
+	"context"
 	"runtime"
 	"sync"
 	"sync/atomic"
@@ -24,18 +24,64 @@ type requestInner struct {
 	// self-dependency checking without acquiring any locks.
 	responsible atomic.Pointer[workerInner]
 
+	// lazy is set only for requests created by [NewLazyRequest], in which
+	// case it's the function that will produce the result on behalf of
+	// whichever worker awaits the request first. The first awaiter swaps
+	// this to nil to claim responsibility for running it.
+	lazy atomic.Pointer[func(*Worker)]
+
 	mu     sync.Mutex
-	cond   *sync.Cond
 	result atomic.Pointer[requestResult]
+
+	// waitStats must be accessed only while holding mu, and is updated only
+	// when enabled by [SetWaitStatsEnabled].
+	waitStats waitStatsCollector
+
+	// waiters is the number of goroutines currently blocked in
+	// [requestInner.wait], as reported by [Promise.WaiterCount].
+	waiters atomic.Int32
+
+	// done is a channel that's closed once the request is resolved, which
+	// is how waiters block until resolution. This is created lazily by
+	// [requestInner.doneChan] only if someone needs to wait, so that
+	// requests that are resolved before anyone awaits them don't need to
+	// allocate it, and must be accessed only while holding mu.
+	done chan struct{}
+
+	// onResolve are the callbacks registered by [Promise.OnResolve] that
+	// have not yet been called. This must be accessed only while holding mu.
+	onResolve []func(*requestResult)
+
+	// name is an optional label given to [NewRequestNamed], used only in
+	// diagnostic messages.
+	name string
+
+	// serial is a number assigned to the request when it's created, which
+	// is unique among all requests in the program and never zero. This
+	// allows a [RequestID] to still be described after the request has
+	// been garbage collected.
+	serial uint64
 }
 
-func (ri *requestInner) ResultID() RequestID {
+// lastRequestSerial is the serial number most recently assigned to a
+// request. Zero is never assigned, so that it can represent [NoRequest].
+var lastRequestSerial atomic.Uint64
+
+func (ri *requestInner) RequestID() RequestID {
+	if ri == nil {
+		return NoRequest
+	}
 	return RequestID{
-		ptr: weak.Make(ri),
+		ptr:    weak.Make(ri),
+		serial: ri.serial,
 	}
 }
 
-func (ri *requestInner) await(requestingWorker *Worker) *requestResult {
+// await blocks until the request is resolved or until the given context is
+// cancelled. The error result is non-nil only if the context was cancelled
+// before the request was resolved, or if the worker has exhausted the budget
+// set by [NewWorkerWithAwaitBudget], in which case the result is nil.
+func (ri *requestInner) await(ctx context.Context, requestingWorker *Worker) (*requestResult, error) {
 	// This function deals with the "slow-path" await, after
 	// [Promise.Await] dealt with some fast-path situations. However,
 	// we haven't been holding any locks so far and so we'll need to recheck
@@ -49,69 +95,148 @@ func (ri *requestInner) await(requestingWorker *Worker) *requestResult {
 	// to Go. We use atomic memory accesses to avoid acquring broadly-scoped
 	// locks that would likely cause contention between workers.
 
-	swapped := requestingWorker.inner.awaiting.CompareAndSwap(nil, ri)
-	if !swapped {
+	if err := ctx.Err(); err != nil {
+		// If the context is already cancelled then we won't even register
+		// that we're awaiting, since we're not going to block.
+		return nil, err
+	}
+	if requestingWorker == nil {
+		// A nil worker represents a "root" waiter that isn't a worker at
+		// all, and so cannot be responsible for any requests and cannot
+		// participate in a self-dependency cycle.
+		return ri.wait(ctx, nil)
+	}
+
+	// If another goroutine is already awaiting this same request using this
+	// worker then we join its wait, and the worker remains awaiting the
+	// request until both of us are done.
+	joined, ok := requestingWorker.inner.beginAwait(ri)
+	if !ok {
 		// Apparently another goroutine has begun waiting with this worker
 		// in the meantime since [Promise.Await] did its initial check.
-		panic(fmt.Sprintf("worker %p awaits multiple promises", requestingWorker.inner))
-	}
-	defer func() {
-		// Before we return we need to set "awaiting" back to nil again to
-		// let the requesting worker await other promises, but since we might
-		// return before we aquire locks we could again be racing with another
-		// goroutine trying to use the same worker object, so we'll detect
-		// that here.
-		// (This corresponds to the "try/finally" pseudocode in at the
-		// end of the algorithm from the paper, since Go does not have
-		// exceptions.)
-		swappedBack := requestingWorker.inner.awaiting.CompareAndSwap(ri, nil)
-		if !swappedBack {
-			panic(fmt.Sprintf("worker %p awaits multiple promises", requestingWorker.inner))
-		}
-	}()
+		panic(requestingWorker.inner.errConcurrentAwait(ri))
+	}
+	// Before we return we need to set "awaiting" back to nil again to let
+	// the requesting worker await other promises, unless another goroutine
+	// is still awaiting the same request.
+	// (This corresponds to the "try/finally" pseudocode in at the
+	// end of the algorithm from the paper, since Go does not have
+	// exceptions.)
+	defer requestingWorker.inner.endAwait(ri)
 
 	// Before we begin waiting we'll check whether our change to the "awaiting"
-	// field above has caused a cycle in the worker-result graph. Because each
-	// worker awaits zero or one results and each result has exactly one
+	// field above has caused a cycle in the worker-request graph. Because each
+	// worker awaits zero or one requests and each request has exactly one
 	// responsible worker we can check this using only a linear walk along those
 	// edges.
-	selfDependency, _ := detectSelfDependency(ri, requestingWorker.inner, false)
+	// Callers can opt out of this check if they've promised that their
+	// dependency graph is acyclic, in which case a cycle will deadlock.
+	// Callers can also opt in to collecting the requests in the cycle during
+	// this first walk, so that we don't need to walk again if we find one.
+	// A goroutine that joined an existing wait skips this, because the
+	// graph is unchanged since the first goroutine checked it. The await
+	// budget, the onBlock callback, and the observer still apply to it.
+	selfDependency := false
+	var cycle []*requestInner
+	if !joined && !requestingWorker.skipSelfDependencyChecks.Load() {
+		selfDependency, cycle = detectSelfDependency(ri, requestingWorker.inner, collectCyclesEagerly.Load())
+	}
 	if selfDependency {
-		// We've found a self-dependency but we want to be able to report
-		// which requests were affected by it and so we'll repeat the same
-		// work again but this time collect up all of the request nodes we
-		// encounter along the way. This redundancy allows us to avoid
-		// allocating the request slice on the happy path. We could potentially
-		// get a slightly different result this time but nonetheless we'll
-		// still be reporting at least some of the results that were affected
-		// by the cycle.
-		_, failedResults := detectSelfDependency(ri, requestingWorker.inner, true)
-		resultIDs := make([]RequestID, 0, len(failedResults))
-		for _, result := range failedResults {
-			resultIDs = append(resultIDs, result.ResultID())
+		// Note that this resolves "ri" as a side-effect, since it will
+		// always be one of the requests in the cycle. Therefore we can fall
+		// through here and detect below that the result is now resolved.
+		resolveSelfDependency(ri, requestingWorker.inner, cycle)
+	}
+
+	// A worker with an await budget must now spend some of it, unless the
+	// request is already resolved and so we won't actually block.
+	if budget := requestingWorker.awaitBudget; budget != nil && ri.result.Load() == nil {
+		if budget.remaining.Add(-1) < 0 {
+			return nil, ErrAwaitBudgetExceeded{
+				RequestID: ri.RequestID(),
+				Budget:    budget.limit,
+			}
 		}
-		err := ErrSelfDependency{RequestIDs: resultIDs}
-		for _, result := range failedResults {
-			result.resolveUsageFault(err)
+	}
+
+	// We'll now finally actually wait, since we know it's now safe for us
+	// to block without causing a deadlock.
+	if o := observer.Load(); o != nil {
+		workerID, reqID := requestingWorker.inner.WorkerID(), ri.RequestID()
+		(*o).OnAwaitStart(workerID, reqID)
+		result, err := ri.wait(ctx, requestingWorker.onBlock)
+		if result != nil {
+			(*o).OnAwaitEnd(workerID, reqID, result.err)
+		} else {
+			(*o).OnAwaitEnd(workerID, reqID, err)
 		}
-		// Note that we've now resolved "ri" as a side-effect of the above,
-		// since it will always be one of the failed results. Therefore we
-		// can fall through here and detect below that the result is now
-		// resolved.
+		return result, err
 	}
+	return ri.wait(ctx, requestingWorker.onBlock)
+}
 
-	// We'll now finally actually aquire the lock, since we know it's now
-	// safe for us to block without causing a deadlock.
+// wait is the final part of [requestInner.await], which blocks until the
+// request is resolved or the context is cancelled, after the caller has
+// already dealt with self-dependency detection.
+//
+// If onBlock is not nil then it's called once before blocking, as described
+// in [Worker.SetOnBlock].
+func (ri *requestInner) wait(ctx context.Context, onBlock func(RequestID)) (*requestResult, error) {
 	ri.mu.Lock()
-	for {
-		if resolution := ri.result.Load(); resolution != nil {
+	if resolution := ri.result.Load(); resolution != nil {
+		ri.mu.Unlock()
+		return resolution, nil
+	}
+	if err := ctx.Err(); err != nil {
+		ri.mu.Unlock()
+		return nil, err
+	}
+	done := ri.doneChanLocked()
+	ri.waiters.Add(1)
+	defer ri.waiters.Add(-1)
+	if waitStatsEnabled.Load() {
+		ri.waitStats.enter()
+		defer func() {
+			ri.mu.Lock()
+			ri.waitStats.exit()
 			ri.mu.Unlock()
-			return resolution
+		}()
+	}
+	ri.mu.Unlock()
+
+	if onBlock != nil {
+		// We call the callback without holding our lock in case it
+		// interacts with this request in some way.
+		onBlock(ri.RequestID())
+	}
+	select {
+	case <-done:
+		return ri.result.Load(), nil
+	case <-ctx.Done():
+		// If the request was resolved at the same time as the context was
+		// cancelled then we prefer to return the result.
+		if resolution := ri.result.Load(); resolution != nil {
+			return resolution, nil
 		}
-		ri.cond.Wait() // ri.mu is automatically unlocked while waiting, and then relocked before this returns
+		return nil, ctx.Err()
 	}
 }
 
+// claimLazy attempts to claim the lazy function of a request created by
+// [NewLazyRequest] on behalf of the given worker, making that worker
+// responsible for the request and returning the function it must now run.
+//
+// Returns nil if the request is not lazy or if some other worker has already
+// claimed it.
+func (ri *requestInner) claimLazy(requestingWorker *Worker) func(*Worker) {
+	run := ri.lazy.Swap(nil)
+	if run == nil {
+		return nil
+	}
+	ri.setResponsibleWorker(requestingWorker.inner)
+	return *run
+}
+
 // detectSelfDependency is the main loop for self-dependency detection in
 // [requestInner.await], factored out so that we can run it a second time in
 // a more expensive mode (with collectFailedReqs set) to collect context when
@@ -126,7 +251,9 @@ func detectSelfDependency(currentReq *requestInner, requestingWorker *workerInne
 		failedReqs = append(failedReqs, currentReq)
 	}
 	for currentWorker != requestingWorker {
-		if currentReq == nil {
+		if currentReq == nil || currentWorker == nil {
+			// A nil worker means that the request was already settled
+			// when it was created, so it cannot be part of a cycle.
 			break
 		}
 		nextReq := currentWorker.awaiting.Load()
@@ -147,38 +274,134 @@ func detectSelfDependency(currentReq *requestInner, requestingWorker *workerInne
 	return currentWorker == requestingWorker, failedReqs
 }
 
+// resolveSelfDependency resolves all of the requests in the self-dependency
+// cycle that begins with the given request and ends with the given worker,
+// after [detectSelfDependency] has already reported that there is one.
+//
+// failedReqs is the set of requests that [detectSelfDependency] collected,
+// if the caller asked it to collect them, or nil otherwise.
+func resolveSelfDependency(ri *requestInner, requestingWorker *workerInner, failedReqs []*requestInner) {
+	if failedReqs == nil {
+		// We've found a self-dependency but we want to be able to report
+		// which requests were affected by it and so we'll repeat the same
+		// work again but this time collect up all of the request nodes we
+		// encounter along the way. This redundancy allows us to avoid
+		// allocating the request slice on the happy path. We could potentially
+		// get a slightly different result this time but nonetheless we'll
+		// still be reporting at least some of the requests that were affected
+		// by the cycle.
+		_, failedReqs = detectSelfDependency(ri, requestingWorker, true)
+	}
+	reqIDs := make([]RequestID, 0, len(failedReqs))
+	for _, req := range failedReqs {
+		reqIDs = append(reqIDs, req.RequestID())
+	}
+	// The requests were collected in the order we walked them, starting
+	// with the one the requesting worker is awaiting and ending with one
+	// that it's responsible for, so returning to the first request
+	// closes the cycle.
+	cyclePath := append(reqIDs[:len(reqIDs):len(reqIDs)], reqIDs[0])
+	err := ErrSelfDependency{RequestIDs: reqIDs, CyclePath: cyclePath}
+	for _, req := range failedReqs {
+		req.resolveUsageFault(err)
+	}
+}
+
 // resolveExplicit is the main resolution function for an "explicit" result,
 // meaning that the result is being provided by the worker that's responsible
 // for doing so.
 func (ri *requestInner) resolveExplicit(resolvingWorker *Worker, val any, err error) {
+	ri.resolveExplicitResult(resolvingWorker, newExplicitResult(resolvingWorker.inner, val, err))
+}
+
+// resolveExplicitResult is the part of [requestInner.resolveExplicit] that
+// deals with an already-constructed result, so that callers resolving many
+// requests with the same outcome can share a single result object.
+//
+// This panics if the request cannot be resolved by the given worker, because
+// that always represents a bug in the caller. Use
+// [requestInner.tryResolveExplicitResult] to handle those problems as
+// errors instead.
+func (ri *requestInner) resolveExplicitResult(resolvingWorker *Worker, result *requestResult) {
+	if err := ri.tryResolveExplicitResult(resolvingWorker, result); err != nil {
+		panic(err.Error())
+	}
+}
+
+// tryResolveExplicitResult is like [requestInner.resolveExplicitResult]
+// except that it returns [ErrNotResponsible] if the given worker is not
+// responsible for the request, or [ErrAlreadyResolved] if the request was
+// already resolved explicitly, leaving the request unchanged in both cases.
+func (ri *requestInner) tryResolveExplicitResult(resolvingWorker *Worker, result *requestResult) error {
+	wake, err := ri.storeExplicitResult(resolvingWorker, result)
+	wake.run()
+	return err
+}
+
+// storeExplicitResult is the main implementation of
+// [requestInner.tryResolveExplicitResult], which stores the result but
+// leaves the caller to wake any waiters using the returned [wakeup] once
+// it's ready for them to observe the result.
+//
+// Resolvers of several requests can therefore store all of their results
+// before waking any waiters. A worker that begins awaiting in the meantime
+// observes the stored result immediately, so the caller must always
+// eventually run the returned wakeup.
+func (ri *requestInner) storeExplicitResult(resolvingWorker *Worker, result *requestResult) (wake wakeup, err error) {
 	ri.mu.Lock()
 	defer ri.mu.Unlock()
 	if got, want := resolvingWorker.inner, ri.responsible.Load(); got != want {
-		panic(fmt.Sprintf("request was resolved by worker %p, but %p was responsible", got, want))
+		return wake, ri.errNotResponsible(got, want)
 	}
 	if resolution := ri.result.Load(); resolution != nil {
 		// This is already resolved. If it was resolved with a usage error then
 		// we'll just silently ignore this call to avoid changing the
 		// previously-reported outcome, but if the previous resolution was also
-		// explicit then that suggests a bug in the caller and so we'll panic.
+		// explicit then that suggests a bug in the caller.
 		if resolution.IsExplicit() {
-			panic("request resolved multiple times")
+			return wake, ErrAlreadyResolved{RequestID: ri.RequestID()}
 		}
-		return
+		return wake, nil
 	}
 	resolvingWorker.inner.mu.Lock()
 	defer resolvingWorker.inner.mu.Unlock()
 	delete(resolvingWorker.inner.responsibleFor, ri)
 
-	ri.result.Store(newExplicitResult(val, err))
-	ri.cond.Broadcast()
+	ri.result.Store(result)
+	// Once the result is stored no new done channel can be created, so
+	// the wakeup can safely close this one after we release the lock.
+	wake = wakeup{
+		done:      ri.done,
+		callbacks: ri.takeOnResolve(),
+		result:    result,
+	}
+	countResolved(nil)
 
 	// We'll make sure that Worker can't get collected until we're ready to
 	// return just to avoid any oddities that might arise if we have the
 	// last remaining pointer to this Worker object. (If we let it become dead
-	// too soon then all of the results it's responsible for -- presumably
+	// too soon then all of the requests it's responsible for -- presumably
 	// including this one -- could get force-resolved with [ErrUnresolved].
 	runtime.KeepAlive(resolvingWorker)
+	return wake, nil
+}
+
+// wakeup describes the work needed to notify waiters that a request has been
+// resolved, as returned by [requestInner.storeExplicitResult]. The zero value
+// does nothing.
+type wakeup struct {
+	done      chan struct{}
+	callbacks []func(*requestResult)
+	result    *requestResult
+}
+
+// run wakes the waiters and then calls any OnResolve callbacks. This must
+// be called without holding any locks.
+func (wake wakeup) run() {
+	if wake.done != nil {
+		close(wake.done)
+	}
+	runOnResolve(wake.callbacks, wake.result)
 }
 
 // resolveUsageFault is a variant resolution function for situations where we
@@ -186,43 +409,217 @@ func (ri *requestInner) resolveExplicit(resolvingWorker *Worker, val any, err er
 // library has been used incorrectly.
 func (ri *requestInner) resolveUsageFault(err error) {
 	ri.mu.Lock()
-	defer ri.mu.Unlock()
+	result, callbacks := ri.resolveUsageFaultLocked(err)
+	ri.mu.Unlock()
+	runOnResolve(callbacks, result)
+}
+
+// abandon is the implementation of [Resolver.Abandon], which resolves the
+// request with [ErrAbandoned] unless it's already resolved.
+//
+// It returns [ErrNotResponsible] without changing anything if the given
+// worker is not responsible for the request. The check and the resolution
+// happen under the same lock so that the request can't be delegated to
+// another worker in between.
+func (ri *requestInner) abandon(resolvingWorker *Worker) error {
+	ri.mu.Lock()
+	if got, want := resolvingWorker.inner, ri.responsible.Load(); got != want {
+		ri.mu.Unlock()
+		return ri.errNotResponsible(got, want)
+	}
+	result, callbacks := ri.resolveUsageFaultLocked(ErrAbandoned{RequestID: ri.RequestID()})
+	ri.mu.Unlock()
+	runOnResolve(callbacks, result)
+	return nil
+}
+
+// resolveUsageFaultLocked is the main implementation of
+// [requestInner.resolveUsageFault], which must be called while holding mu.
+// It returns the new result and the callbacks that the caller must run
+// with it once it has released mu, or no callbacks if the request was
+// already resolved.
+func (ri *requestInner) resolveUsageFaultLocked(err error) (*requestResult, []func(*requestResult)) {
 	if result := ri.result.Load(); result != nil {
 		// This is already resolved, so we'll leave the existing resolution
 		// in place because some consumers might already have observed the
 		// previous resolution.
-		return
+		return result, nil
 	}
 
-	ri.result.Store(newUsageFaultResult(err))
-	ri.cond.Broadcast()
+	result := newUsageFaultResult(err)
+	ri.result.Store(result)
+	ri.closeDone()
+	callbacks := ri.takeOnResolve()
+	countResolved(err)
+
+	// The responsible worker is no longer responsible for a resolved
+	// request. We must load this only after storing the result so that
+	// we'll agree with a concurrent [requestInner.setResponsibleWorker]
+	// about which worker needs to forget the request.
+	if wi := ri.responsible.Load(); wi != nil {
+		wi.mu.Lock()
+		delete(wi.responsibleFor, ri)
+		wi.mu.Unlock()
+	}
+	return result, callbacks
 }
 
-func newRequestInner(responsibleWorker *workerInner) *requestInner {
-	ret := &requestInner{}
-	ret.cond = sync.NewCond(&ret.mu)
+// addOnResolve registers a callback to be called once the request is
+// resolved, returning false without registering it if the request is
+// already resolved.
+func (ri *requestInner) addOnResolve(f func(*requestResult)) bool {
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+	if ri.result.Load() != nil {
+		return false
+	}
+	ri.onResolve = append(ri.onResolve, f)
+	return true
+}
+
+// takeOnResolve returns the callbacks registered by
+// [requestInner.addOnResolve] and forgets them, so that each is called only
+// once. This must be called while holding mu, immediately after storing the
+// result, and then the caller must pass the callbacks to [runOnResolve]
+// after releasing all of its locks.
+func (ri *requestInner) takeOnResolve() []func(*requestResult) {
+	ret := ri.onResolve
+	ri.onResolve = nil
+	return ret
+}
+
+func runOnResolve(callbacks []func(*requestResult), result *requestResult) {
+	for _, f := range callbacks {
+		f(result)
+	}
+}
+
+func newRequestInner(responsibleWorker *workerInner, name string) *requestInner {
+	ret := &requestInner{
+		name:   name,
+		serial: lastRequestSerial.Add(1),
+	}
+	stats.requestsTotal.Add(1)
+	stats.inFlight.Add(1)
 	ret.setResponsibleWorker(responsibleWorker)
 	return ret
 }
 
+// newRequestInners is like [newRequestInner] but creates n requests at
+// once, using a single allocation for all of them.
+//
+// Because the requests share an allocation, none of them can be garbage
+// collected until all of them are unreachable.
+func newRequestInners(responsibleWorker *workerInner, n int) []requestInner {
+	ret := make([]requestInner, n)
+	stats.requestsTotal.Add(int64(n))
+	stats.inFlight.Add(int64(n))
+	first := lastRequestSerial.Add(uint64(n)) - uint64(n) + 1
+	for i := range ret {
+		ret[i].serial = first + uint64(i)
+		ret[i].setResponsibleWorker(responsibleWorker)
+	}
+	return ret
+}
+
+// doneChan returns a channel that is closed once the request is resolved.
+func (ri *requestInner) doneChan() <-chan struct{} {
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+	return ri.doneChanLocked()
+}
+
+// doneChanLocked is the main implementation of [requestInner.doneChan],
+// which must be called only while holding mu.
+func (ri *requestInner) doneChanLocked() <-chan struct{} {
+	if ri.result.Load() != nil {
+		return closedChan
+	}
+	if ri.done == nil {
+		ri.done = make(chan struct{})
+	}
+	return ri.done
+}
+
+// errNotResponsible returns the error describing an attempt by the worker
+// got to resolve the request when the worker want is responsible for it.
+func (ri *requestInner) errNotResponsible(got, want *workerInner) ErrNotResponsible {
+	var expected WorkerID
+	if want != nil {
+		expected = want.WorkerID()
+	}
+	return ErrNotResponsible{
+		RequestID: ri.RequestID(),
+		Expected:  expected,
+		Actual:    got.WorkerID(),
+	}
+}
+
+// closeDone closes the channel returned by [requestInner.doneChan], if any.
+// This must be called while holding mu, immediately after storing the result.
+func (ri *requestInner) closeDone() {
+	if ri.done != nil {
+		close(ri.done)
+	}
+}
+
+// closedChan is a channel that's always closed, which we return from
+// [requestInner.doneChan] for requests that are already resolved.
+var closedChan = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
+// newSettledRequestInner returns a request that is already resolved with
+// the given result, and which therefore has no responsible worker.
+func newSettledRequestInner(result *requestResult) *requestInner {
+	ret := &requestInner{
+		serial: lastRequestSerial.Add(1),
+	}
+	ret.result.Store(result)
+	return ret
+}
+
 func (ri *requestInner) setResponsibleWorker(new *workerInner) {
 	new.mu.Lock()
-	defer new.mu.Unlock()
 	old := ri.responsible.Swap(new)
-	if old != nil {
+	if ri.result.Load() == nil {
+		// A request that's already resolved no longer needs anyone to
+		// be responsible for it, but we still update the responsible
+		// pointer above so that a late attempt to resolve it explicitly
+		// behaves consistently with the delegation.
+		new.responsibleFor[ri] = struct{}{}
+	}
+	dropped := new.dropped
+	new.mu.Unlock()
+
+	// We must not hold the new worker's lock while acquiring the old
+	// worker's, because a concurrent delegation in the opposite direction
+	// would acquire the same two locks in the opposite order.
+	if old != nil && old != new {
 		old.mu.Lock()
-		defer old.mu.Unlock()
 		delete(old.responsibleFor, ri)
+		old.mu.Unlock()
+	}
+
+	if dropped {
+		// A worker that was already dropped can never resolve this request.
+		ri.resolveUsageFault(new.dropError(ri))
 	}
-	new.responsibleFor[ri] = struct{}{}
 }
 
 type requestResult struct {
 	value any
 	err   error
+
+	// resolvedBy is the worker that provided an explicit result, or
+	// the zero value for a usage fault result or for an explicit result
+	// that was settled without any worker.
+	resolvedBy WorkerID
 }
 
-func newExplicitResult(value any, err error) *requestResult {
+func newExplicitResult(resolvingWorker *workerInner, value any, err error) *requestResult {
 	if value == nil {
 		// Should not be possible because we should always get here through
 		// a generic function that enforces value always being a valid value
@@ -230,10 +627,14 @@ func newExplicitResult(value any, err error) *requestResult {
 		// can be nil, the interface value containing it would not be nil.)
 		panic("explicit resolution with nil value")
 	}
-	return &requestResult{
+	ret := &requestResult{
 		value: value,
 		err:   err,
 	}
+	if resolvingWorker != nil {
+		ret.resolvedBy = resolvingWorker.WorkerID()
+	}
+	return ret
 }
 
 func newUsageFaultResult(err error) *requestResult {
@@ -249,6 +650,32 @@ func (rr *requestResult) IsExplicit() bool {
 	return rr.value != nil
 }
 
+// Kind classifies the result, as reported by [Promise.AwaitDetailed].
+func (rr *requestResult) Kind() ResolutionKind {
+	if rr.IsExplicit() {
+		return ResolutionExplicit
+	}
+	switch rr.err.(type) {
+	case ErrSelfDependency:
+		return ResolutionSelfDependency
+	case ErrUnresolved:
+		return ResolutionUnresolved
+	default:
+		return ResolutionUsageFault
+	}
+}
+
+// resolvedBy returns the worker that explicitly resolved the request, if any.
+func (ri *requestInner) resolvedBy() (WorkerID, bool) {
+	result := ri.result.Load()
+	if result == nil || !result.IsExplicit() || result.resolvedBy == (WorkerID{}) {
+		// Settled results created without any worker also have no
+		// resolving worker to report.
+		return WorkerID{}, false
+	}
+	return result.resolvedBy, true
+}
+
 func resultRet[T any](result *requestResult) (T, error) {
 	// The type assertion below should fail only if value is nil to
 	// represent a usage error, in which case we'll just return the
@@ -0,0 +1,88 @@
+package workgraph_test
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/apparentlymart/go-workgraph/workgraph"
+)
+
+func TestNewRequests(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolvers, promises := workgraph.NewRequests[int](mainWorker, 3)
+	if got, want := len(mainWorker.ResponsibleFor()), 3; got != want {
+		t.Fatalf("worker is responsible for %d requests; want %d", got, want)
+	}
+	if resolvers[0].RequestID() == resolvers[1].RequestID() {
+		t.Fatal("requests in the same batch have the same RequestID")
+	}
+
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		for i, resolver := range resolvers {
+			resolver.ReportSuccess(w, i*10)
+		}
+	}, resolversContainer[int](resolvers))
+
+	got, err := workgraph.AwaitAll(mainWorker, promises)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for i, v := range got {
+		if want := i * 10; v != want {
+			t.Errorf("wrong result %d for request %d; want %d", v, i, want)
+		}
+	}
+}
+
+func BenchmarkNewRequest(b *testing.B) {
+	const batchSize = 1000
+	w := workgraph.NewWorker()
+	b.ReportAllocs()
+	for b.Loop() {
+		for range batchSize {
+			resolver, _ := workgraph.NewRequest[int](w)
+			resolver.ReportSuccess(w, 0)
+		}
+	}
+}
+
+func BenchmarkNewRequests(b *testing.B) {
+	const batchSize = 1000
+	w := workgraph.NewWorker()
+	b.ReportAllocs()
+	for b.Loop() {
+		resolvers, _ := workgraph.NewRequests[int](w, batchSize)
+		for _, resolver := range resolvers {
+			resolver.ReportSuccess(w, 0)
+		}
+	}
+}
+
+func BenchmarkAwaitFanOut(b *testing.B) {
+	for _, numWaiters := range []int{1, 10, 100} {
+		b.Run(fmt.Sprintf("%d waiters", numWaiters), func(b *testing.B) {
+			mainWorker := workgraph.NewWorker()
+			b.ReportAllocs()
+			for b.Loop() {
+				resolver, promise := workgraph.NewRequest[int](mainWorker)
+				var wg sync.WaitGroup
+				for range numWaiters {
+					wg.Add(1)
+					go func() {
+						promise.Await(workgraph.NewWorker())
+						wg.Done()
+					}()
+				}
+				// We wait until all of the waiters are blocked so that we're
+				// measuring the cost of waking them up.
+				for promise.WaiterCount() < numWaiters {
+					runtime.Gosched()
+				}
+				resolver.ReportSuccess(mainWorker, 0)
+				wg.Wait()
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package workgraph
 
 import (
 	"iter"
+	"runtime"
 )
 
 // A Resolver is used by the [Worker] that is responsible for resolving a
@@ -20,6 +21,25 @@ func (r Resolver[T]) Report(resolvingWorker *Worker, val T, err error) {
 	r.inner.resolveExplicit(resolvingWorker, val, err)
 }
 
+// TryReport is like [Resolver.Report] except that it returns an error
+// instead of panicking if the request cannot be resolved by the given worker.
+//
+// If the given worker is not responsible for the request then the result is
+// [ErrNotResponsible]. If the request was already resolved by an earlier
+// report then the result is [ErrAlreadyResolved], and the earlier result is
+// retained.
+//
+// This is intended for defensive code that must not crash the program even
+// if some other part of the program has a bug. Both situations still
+// represent a bug, so most callers should use Report.
+//
+// As with Report, a request that this library already resolved to report
+// a usage fault, such as [ErrSelfDependency], silently ignores any later
+// report, and so TryReport returns nil in that case.
+func (r Resolver[T]) TryReport(resolvingWorker *Worker, val T, err error) error {
+	return r.inner.tryResolveExplicitResult(resolvingWorker, newExplicitResult(resolvingWorker.inner, val, err))
+}
+
 // ReportSuccess is a helper for [Resolver.Report] which automatically sets
 // the error to nil, suggesting a successful result.
 func (r Resolver[T]) ReportSuccess(resolvingWorker *Worker, val T) {
@@ -34,13 +54,145 @@ func (r Resolver[T]) ReportError(resolvingWorker *Worker, err error) {
 	r.Report(resolvingWorker, zero, err)
 }
 
+// Abandon resolves the request with [ErrAbandoned], for a worker that has
+// decided that it cannot produce a result for this particular request but
+// is otherwise continuing its work.
+//
+// This is distinct from reporting an error with [Resolver.ReportError],
+// because the request is resolved in the same way as when this library
+// detects a usage fault, such as [ErrUnresolved] when a worker is dropped.
+// In particular, [Resolver.ResolvedBy] reports that no worker resolved it.
+//
+// The given worker must be responsible for the request, and Abandon panics
+// if it isn't. If the request was already resolved then Abandon has no
+// effect.
+func (r Resolver[T]) Abandon(resolvingWorker *Worker) {
+	if err := r.inner.abandon(resolvingWorker); err != nil {
+		panic(err.Error())
+	}
+	runtime.KeepAlive(resolvingWorker)
+}
+
+// ResolveFrom awaits the given promise using the given worker, which must be
+// responsible for the receiver's request, and then resolves the receiver's
+// request with the same value and error.
+//
+// This is useful for forwarding the outcome of some other request that
+// the worker depends on without otherwise transforming it.
+//
+// If the given promise depends, directly or indirectly, on the receiver's
+// request then the await completes a self-dependency cycle, and so both
+// requests fail with [ErrSelfDependency] as usual.
+func (r Resolver[T]) ResolveFrom(resolvingWorker *Worker, src Promise[T]) {
+	val, err := src.Await(resolvingWorker)
+	r.Report(resolvingWorker, val, err)
+}
+
+// Broadcast resolves all of the given requests with the same value and error,
+// as if calling [Resolver.Report] on each of them in turn.
+//
+// This is an optimization for situations where a single outcome satisfies
+// many requests at once: all of the requests share a single internal
+// representation of the result instead of each allocating their own.
+//
+// The given worker must be responsible for all of the given requests, just
+// as it would need to be to call [Resolver.Report] on each one separately.
+func Broadcast[T any](resolvingWorker *Worker, resolvers []Resolver[T], val T, err error) {
+	result := newExplicitResult(resolvingWorker.inner, val, err)
+	for _, r := range resolvers {
+		r.inner.resolveExplicitResult(resolvingWorker, result)
+	}
+}
+
+// Resolution is a result for a particular request, for use with [ReportAll].
+type Resolution[T any] struct {
+	Resolver Resolver[T]
+	Value    T
+	Err      error
+}
+
+// ReportAll resolves each of the given requests with its corresponding
+// value and error, as if calling [Resolver.Report] on each of them in turn,
+// except that no waiters are woken until all of the results are stored.
+//
+// This is intended for a worker that produces several results together. A
+// waiter that wakes because one of the requests was resolved and then awaits
+// another of them finds it already resolved, rather than blocking again.
+//
+// The given worker must be responsible for all of the given requests. If it
+// isn't, or if any of the requests was already resolved, then ReportAll
+// panics after waking the waiters of any requests it had already resolved.
+func ReportAll[T any](resolvingWorker *Worker, resolutions []Resolution[T]) {
+	wakes := make([]wakeup, 0, len(resolutions))
+	var err error
+	for _, r := range resolutions {
+		var wake wakeup
+		result := newExplicitResult(resolvingWorker.inner, r.Value, r.Err)
+		wake, err = r.Resolver.inner.storeExplicitResult(resolvingWorker, result)
+		wakes = append(wakes, wake)
+		if err != nil {
+			break
+		}
+	}
+	for _, wake := range wakes {
+		wake.run()
+	}
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+// ReportLatest starts a new worker that takes responsibility for the given
+// resolver and then reads values from the given channel until it's closed,
+// at which point it resolves the request with the last value it received.
+//
+// If the channel is closed without producing any values then the request
+// is resolved with the zero value of T.
+//
+// As with [NewWorker], the caller must be responsible for the given resolver
+// at the time of the call, and delegates that responsibility to the new
+// worker. If the new worker is somehow dropped before the channel is closed
+// then the request fails with [ErrUnresolved] as usual.
+func ReportLatest[T any](r Resolver[T], ch <-chan T) {
+	WithNewAsyncWorker(func(w *Worker) {
+		var latest T
+		for v := range ch {
+			latest = v
+		}
+		r.ReportSuccess(w, latest)
+	}, r)
+}
+
 // RequestID returns a unique identifier for the request that this resolver
 // belongs to.
 //
 // This can be compared with [RequestID] values in errors returned by this
 // library in situations that would otherwise cause a deadlock.
 func (r Resolver[T]) RequestID() RequestID {
-	return r.inner.ResultID()
+	return r.inner.RequestID()
+}
+
+// ResolvedBy returns the identifier of the worker that resolved the request,
+// which could be either the worker that was originally responsible for it or
+// some other worker that responsibility was later delegated to.
+//
+// The second return value is false if the request is not yet resolved, or
+// if it was resolved by this library to report a usage fault, such as
+// [ErrSelfDependency] or [ErrUnresolved], rather than by a worker.
+func (r Resolver[T]) ResolvedBy() (WorkerID, bool) {
+	return r.inner.resolvedBy()
+}
+
+// WaitStats returns statistics about the workers that have waited for the
+// request to be resolved.
+//
+// The result is always the zero value unless statistics collection was
+// enabled using [SetWaitStatsEnabled] while workers were awaiting the
+// request.
+func (r Resolver[T]) WaitStats() WaitStats {
+	r.inner.mu.Lock()
+	defer r.inner.mu.Unlock()
+	return r.inner.waitStats.stats
 }
 
 // ContainedResolvers implements [ResolverContainer], reporting the reciever
@@ -51,8 +203,8 @@ func (r Resolver[T]) ContainedResolvers() iter.Seq[AnyResolver] {
 	}
 }
 
-// resultInner implements AnyResultResolver.
-func (r Resolver[T]) resultInner() *requestInner {
+// requestInner implements AnyResolver.
+func (r Resolver[T]) requestInner() *requestInner {
 	return r.inner
 }
 
@@ -63,7 +215,7 @@ func (r Resolver[T]) resultInner() *requestInner {
 // worker to another, where it doesn't matter what value type each resolver
 // has.
 type AnyResolver interface {
-	resultInner() *requestInner
+	requestInner() *requestInner
 }
 
 var _ AnyResolver = Resolver[int]{}
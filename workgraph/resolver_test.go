@@ -0,0 +1,280 @@
+package workgraph_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/apparentlymart/go-workgraph/workgraph"
+)
+
+func TestBroadcast(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolvers := make([]workgraph.Resolver[string], 3)
+	promises := make([]workgraph.Promise[string], 3)
+	for i := range resolvers {
+		resolvers[i], promises[i] = workgraph.NewRequest[string](mainWorker)
+	}
+	workgraph.Broadcast(mainWorker, resolvers, "hello", nil)
+
+	for i, promise := range promises {
+		got, err := promise.Await(mainWorker)
+		if err != nil {
+			t.Errorf("unexpected error from promise %d: %s", i, err)
+		}
+		if want := "hello"; got != want {
+			t.Errorf("wrong result from promise %d\ngot:  %s\nwant: %s", i, got, want)
+		}
+	}
+}
+
+func TestBroadcast_wrongWorker(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	otherWorker := workgraph.NewWorker()
+	resolver, _ := workgraph.NewRequest[string](mainWorker)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Broadcast did not panic when called by the wrong worker")
+		}
+	}()
+	workgraph.Broadcast(otherWorker, []workgraph.Resolver[string]{resolver}, "hello", nil)
+}
+
+func BenchmarkBroadcast(b *testing.B) {
+	const count = 10000
+
+	b.Run("Report", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			b.StopTimer()
+			w := workgraph.NewWorker()
+			resolvers := newBenchmarkResolvers(w, count)
+			b.StartTimer()
+			for i, r := range resolvers {
+				r.Report(w, i, nil)
+			}
+		}
+	})
+	b.Run("Broadcast", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			b.StopTimer()
+			w := workgraph.NewWorker()
+			resolvers := newBenchmarkResolvers(w, count)
+			b.StartTimer()
+			workgraph.Broadcast(w, resolvers, count, nil)
+		}
+	})
+}
+
+func newBenchmarkResolvers(w *workgraph.Worker, count int) []workgraph.Resolver[int] {
+	ret := make([]workgraph.Resolver[int], count)
+	for i := range ret {
+		ret[i], _ = workgraph.NewRequest[int](w)
+	}
+	return ret
+}
+
+func TestReportLatest(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[int](mainWorker)
+	ch := make(chan int)
+	workgraph.ReportLatest(resolver, ch)
+
+	for i := range 5 {
+		ch <- i
+	}
+	close(ch)
+
+	got, err := promise.Await(mainWorker)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := 4; got != want {
+		t.Errorf("wrong result %d; want %d", got, want)
+	}
+}
+
+func TestResolverTryReport(t *testing.T) {
+	w := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](w)
+
+	if err := resolver.TryReport(w, "first", nil); err != nil {
+		t.Fatalf("unexpected error from first report: %s", err)
+	}
+	err := resolver.TryReport(w, "second", nil)
+	alreadyErr, ok := err.(workgraph.ErrAlreadyResolved)
+	if !ok {
+		t.Fatalf("wrong error %v; want %T", err, alreadyErr)
+	}
+	if alreadyErr.RequestID != resolver.RequestID() {
+		t.Errorf("error has the wrong RequestID")
+	}
+
+	got, _ := promise.Await(w)
+	if want := "first"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestResolverTryReport_notResponsible(t *testing.T) {
+	mainWorker := workgraph.NewNamedWorker("main")
+	otherWorker := workgraph.NewNamedWorker("other")
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+
+	err := resolver.TryReport(otherWorker, "wrong", nil)
+	notRespErr, ok := err.(workgraph.ErrNotResponsible)
+	if !ok {
+		t.Fatalf("wrong error %v; want %T", err, notRespErr)
+	}
+	if notRespErr.RequestID != resolver.RequestID() {
+		t.Errorf("error has the wrong RequestID")
+	}
+	if notRespErr.Expected != mainWorker.ID() {
+		t.Errorf("error has the wrong expected worker")
+	}
+	if notRespErr.Actual != otherWorker.ID() {
+		t.Errorf("error has the wrong actual worker")
+	}
+	if !strings.Contains(err.Error(), `"main"`) || !strings.Contains(err.Error(), `"other"`) {
+		t.Errorf("error message does not mention the worker names: %s", err)
+	}
+	if promise.IsResolved() {
+		t.Errorf("request was resolved by the wrong worker")
+	}
+}
+
+func TestResolverResolveFrom(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+	srcResolver, srcPromise := workgraph.NewRequest[string](mainWorker)
+
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		srcResolver.ReportSuccess(w, "Hello")
+	}, srcResolver)
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		resolver.ResolveFrom(w, srcPromise)
+	}, resolver)
+
+	got, err := promise.Await(mainWorker)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "Hello"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestResolverResolveFrom_selfDependency(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+
+	// The source depends on the request that we're trying to resolve from
+	// it, so forwarding it can never succeed.
+	srcPromise := workgraph.Spawn(mainWorker, func(w *workgraph.Worker) (string, error) {
+		return promise.Await(w)
+	})
+	resolver.ResolveFrom(mainWorker, srcPromise)
+
+	for _, p := range []workgraph.Promise[string]{promise, srcPromise} {
+		_, err := p.Await(workgraph.NewWorker())
+		if _, ok := err.(workgraph.ErrSelfDependency); !ok {
+			t.Errorf("wrong error %v; want %T", err, workgraph.ErrSelfDependency{})
+		}
+	}
+}
+
+func TestReportAll(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolvers := make([]workgraph.Resolver[int], 3)
+	promises := make([]workgraph.Promise[int], 3)
+	for i := range resolvers {
+		resolvers[i], promises[i] = workgraph.NewRequest[int](mainWorker)
+	}
+
+	blocked := make(chan struct{})
+	unresolved := make(chan int, len(promises))
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		w.SetOnBlock(func(workgraph.RequestID) {
+			close(blocked)
+		})
+		promises[0].Await(w)
+		// The whole batch is stored before any waiter wakes, so all of the
+		// other requests must already be resolved by now.
+		for i, promise := range promises {
+			if _, _, ok := promise.TryAwait(); !ok {
+				unresolved <- i
+			}
+		}
+		close(unresolved)
+	})
+	<-blocked
+
+	resolutions := make([]workgraph.Resolution[int], len(resolvers))
+	for i, resolver := range resolvers {
+		resolutions[i] = workgraph.Resolution[int]{Resolver: resolver, Value: i}
+	}
+	resolutions[2].Err = errors.New("oh no")
+	workgraph.ReportAll(mainWorker, resolutions)
+
+	for i := range unresolved {
+		t.Errorf("request %d was not resolved when the first waiter woke", i)
+	}
+	for i, promise := range promises {
+		got, err := promise.Await(mainWorker)
+		if got != i {
+			t.Errorf("wrong value for request %d: %d", i, got)
+		}
+		if wantErr := i == 2; (err != nil) != wantErr {
+			t.Errorf("wrong error for request %d: %v", i, err)
+		}
+	}
+}
+
+func TestResolverAbandon(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+
+	resolver.Abandon(mainWorker)
+	_, err := promise.Await(mainWorker)
+	abandonedErr, ok := err.(workgraph.ErrAbandoned)
+	if !ok {
+		t.Fatalf("wrong error %v; want %T", err, abandonedErr)
+	}
+	if !abandonedErr.RequestID.Equal(resolver.RequestID()) {
+		t.Errorf("wrong request ID %s; want %s", abandonedErr.RequestID, resolver.RequestID())
+	}
+	if _, ok := resolver.ResolvedBy(); ok {
+		t.Error("abandoned request reports that a worker resolved it")
+	}
+	if got := mainWorker.ResponsibleFor(); len(got) != 0 {
+		t.Errorf("worker still responsible for abandoned request %s", got)
+	}
+
+	// A later report is ignored, as for other usage faults.
+	resolver.ReportSuccess(mainWorker, "too late")
+	if _, err := promise.Await(mainWorker); err != abandonedErr {
+		t.Errorf("wrong error after late report %v; want %v", err, abandonedErr)
+	}
+}
+
+func TestResolverAbandon_wrongWorker(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	otherWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+	workgraph.Delegate(otherWorker, resolver)
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("Abandon did not panic when called by the wrong worker")
+			}
+		}()
+		resolver.Abandon(mainWorker)
+	}()
+	if promise.IsResolved() {
+		t.Error("request was resolved by the wrong worker")
+	}
+	resolver.ReportSuccess(otherWorker, "ok")
+}
@@ -0,0 +1,23 @@
+package workgraph
+
+// WithRetry runs f in a new worker, as with [Spawn], and awaits its result
+// using the given worker. If f fails with an error then WithRetry runs it
+// again in another new worker, up to the given total number of attempts,
+// and returns the result of the first successful attempt or the error from
+// the final attempt.
+//
+// Only errors for which [IsRetryable] returns true are retried, and so in
+// particular WithRetry returns [ErrSelfDependency] immediately, because
+// retrying work that depends on itself would just fail in the same way.
+//
+// Each attempt uses a new request, and so a self-dependency involving an
+// earlier attempt cannot affect later ones. WithRetry always makes at least
+// one attempt, even if attempts is less than one.
+func WithRetry[T any](w *Worker, attempts int, f func(*Worker) (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		ret, err := Spawn(w, f).Await(w)
+		if err == nil || attempt >= attempts || !IsRetryable(err) {
+			return ret, err
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package workgraph_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/apparentlymart/go-workgraph/workgraph"
+)
+
+func TestWithRetry(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	calls := 0
+	got, err := workgraph.WithRetry(mainWorker, 3, func(w *workgraph.Worker) (string, error) {
+		calls++
+		if calls < 3 {
+			return "", errors.New("not yet")
+		}
+		return "Hello", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "Hello"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+	if calls != 3 {
+		t.Errorf("function called %d times; want 3", calls)
+	}
+}
+
+func TestWithRetry_exhausted(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	calls := 0
+	errFailed := errors.New("failed")
+	_, err := workgraph.WithRetry(mainWorker, 2, func(w *workgraph.Worker) (string, error) {
+		calls++
+		return "", errFailed
+	})
+	if err != errFailed {
+		t.Errorf("wrong error %v; want %v", err, errFailed)
+	}
+	if calls != 2 {
+		t.Errorf("function called %d times; want 2", calls)
+	}
+}
+
+func TestWithRetry_selfDependency(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	_, promise := workgraph.NewRequest[string](mainWorker)
+	calls := 0
+	_, err := workgraph.WithRetry(mainWorker, 3, func(w *workgraph.Worker) (string, error) {
+		calls++
+		// mainWorker is responsible for this request and is waiting for
+		// us, so this is a self-dependency.
+		return promise.Await(w)
+	})
+	if !errors.Is(err, workgraph.ErrSelfDependency{}) {
+		t.Errorf("wrong error %v; want self-dependency error", err)
+	}
+	if calls != 1 {
+		t.Errorf("function called %d times; want 1", calls)
+	}
+}
@@ -0,0 +1,79 @@
+package workgraph
+
+import (
+	"sync"
+)
+
+// liveWorkers is a set of all of the workers that have not yet been dropped,
+// used by [Snapshot]. The keys are *workerInner and the values are always
+// the empty struct.
+//
+// Holding the inner objects here does not prevent the outer [Worker] objects
+// from being garbage collected, and so the cleanup that eventually drops
+// each worker still runs and removes the worker from this set.
+var liveWorkers sync.Map
+
+// GraphSnapshot is a description of the workers that were live at a
+// particular moment, as returned by [Snapshot].
+type GraphSnapshot struct {
+	Workers []WorkerSnapshot `json:"workers"`
+}
+
+// WorkerSnapshot describes one worker in a [GraphSnapshot].
+type WorkerSnapshot struct {
+	ID WorkerID `json:"id"`
+
+	// Name is the name given to [NewNamedWorker], or empty if the worker
+	// was created without a name.
+	Name string `json:"name,omitempty"`
+
+	// Awaiting is the request that the worker was waiting for, or
+	// [NoRequest] if it was not waiting for anything.
+	Awaiting RequestID `json:"awaiting"`
+
+	// ResponsibleFor lists the unresolved requests that the worker was
+	// responsible for resolving.
+	ResponsibleFor []RequestID `json:"responsible_for"`
+}
+
+// Snapshot returns a description of all of the workers that have not yet
+// been dropped, including which request each one is awaiting and which
+// requests each one is responsible for.
+//
+// This is intended for debugging, such as for understanding which workers
+// are involved in a deadlock that the self-dependency checks cannot detect.
+// Snapshot is safe to call concurrently with other operations, but the
+// workers are examined one at a time while the graph may be changing, and
+// so the result is a best-effort view that might not correspond to any
+// single moment.
+//
+// The identifiers in the result can be serialized using their String
+// methods, or by encoding the snapshot as JSON, and can be correlated with
+// identifiers returned by other functions in this package.
+func Snapshot() GraphSnapshot {
+	var ret GraphSnapshot
+	liveWorkers.Range(func(k, _ any) bool {
+		wi := k.(*workerInner)
+		ret.Workers = append(ret.Workers, wi.snapshot())
+		return true
+	})
+	return ret
+}
+
+func (wi *workerInner) snapshot() WorkerSnapshot {
+	ret := WorkerSnapshot{
+		ID:   wi.WorkerID(),
+		Name: wi.name,
+	}
+	if req := wi.awaiting.Load(); req != nil {
+		ret.Awaiting = req.RequestID()
+	}
+	wi.mu.Lock()
+	for req := range wi.responsibleFor {
+		if req.result.Load() == nil {
+			ret.ResponsibleFor = append(ret.ResponsibleFor, req.RequestID())
+		}
+	}
+	wi.mu.Unlock()
+	return ret
+}
@@ -0,0 +1,73 @@
+package workgraph_test
+
+import (
+	"encoding/json"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/apparentlymart/go-workgraph/workgraph"
+)
+
+func TestSnapshot(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	otherWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](otherWorker)
+
+	blocked := make(chan struct{})
+	mainWorker.SetOnBlock(func(workgraph.RequestID) {
+		close(blocked)
+	})
+	done := make(chan struct{})
+	go func() {
+		promise.Await(mainWorker)
+		close(done)
+	}()
+	<-blocked
+
+	snap := workgraph.Snapshot()
+	mainIdx := slices.IndexFunc(snap.Workers, func(ws workgraph.WorkerSnapshot) bool {
+		return ws.ID == mainWorker.ID()
+	})
+	otherIdx := slices.IndexFunc(snap.Workers, func(ws workgraph.WorkerSnapshot) bool {
+		return ws.ID == otherWorker.ID()
+	})
+	if mainIdx < 0 || otherIdx < 0 {
+		t.Fatalf("snapshot is missing workers: %#v", snap)
+	}
+	if got, want := snap.Workers[mainIdx].Awaiting, resolver.RequestID(); got != want {
+		t.Errorf("main worker awaiting %s; want %s", got, want)
+	}
+	if got, want := snap.Workers[otherIdx].ResponsibleFor, []workgraph.RequestID{resolver.RequestID()}; !slices.Equal(got, want) {
+		t.Errorf("other worker responsible for %s; want %s", got, want)
+	}
+	if _, err := json.Marshal(snap); err != nil {
+		t.Errorf("failed to serialize snapshot: %s", err)
+	}
+
+	resolver.ReportSuccess(otherWorker, "ok")
+	<-done
+	otherID := otherWorker.ID()
+	otherWorker.Cancel(nil)
+	for _, ws := range workgraph.Snapshot().Workers {
+		if ws.ID == otherID {
+			t.Error("snapshot includes a dropped worker")
+		}
+	}
+	runtime.KeepAlive(mainWorker)
+}
+
+func TestSnapshot_namedWorker(t *testing.T) {
+	w := workgraph.NewNamedWorker("example")
+	snap := workgraph.Snapshot()
+	idx := slices.IndexFunc(snap.Workers, func(ws workgraph.WorkerSnapshot) bool {
+		return ws.ID == w.ID()
+	})
+	if idx < 0 {
+		t.Fatal("snapshot is missing the worker")
+	}
+	if got, want := snap.Workers[idx].Name, "example"; got != want {
+		t.Errorf("wrong name %q; want %q", got, want)
+	}
+	runtime.KeepAlive(w)
+}
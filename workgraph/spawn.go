@@ -0,0 +1,49 @@
+package workgraph
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Spawn starts a new worker that runs f on a new goroutine, and returns a
+// promise for the result that f returns.
+//
+// The new worker is responsible for the request from the moment it's
+// created, and so there is no need for the caller to delegate it. The new
+// worker is a child of the given parent worker, as with [NewChildWorker],
+// and so the parent must remain live until f has returned.
+//
+// If f panics then the panic is recovered and the promise fails with
+// [ErrPanic] instead of crashing the program.
+//
+// If f directly or indirectly awaits the promise that Spawn returned then
+// that await fails with [ErrSelfDependency].
+func Spawn[T any](parent *Worker, f func(*Worker) (T, error)) Promise[T] {
+	ret := spawn(parent.inner, f)
+	runtime.KeepAlive(parent)
+	return ret
+}
+
+// spawn is the main implementation of [Spawn], which also allows parent to be
+// nil for internal callers that don't have a worker to use as the parent.
+func spawn[T any](parent *workerInner, f func(*Worker) (T, error)) Promise[T] {
+	w := newWorker(parent, "", nil)
+	resolver, promise := NewRequest[T](w)
+	go func() {
+		ret, err := callRecovering(w, f)
+		resolver.Report(w, ret, err)
+	}()
+	return promise
+}
+
+// callRecovering calls f with the given worker, converting any panic into an
+// [ErrPanic] error.
+func callRecovering[T any](w *Worker, f func(*Worker) (T, error)) (ret T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero T
+			ret, err = zero, ErrPanic{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return f(w)
+}
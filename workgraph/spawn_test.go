@@ -0,0 +1,66 @@
+package workgraph_test
+
+import (
+	"testing"
+
+	"github.com/apparentlymart/go-workgraph/workgraph"
+)
+
+func TestSpawn(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	promise := workgraph.Spawn(mainWorker, func(w *workgraph.Worker) (string, error) {
+		greeting := workgraph.Spawn(w, func(w *workgraph.Worker) (string, error) {
+			return "Hello", nil
+		})
+		name := workgraph.Spawn(w, func(w *workgraph.Worker) (string, error) {
+			return "world", nil
+		})
+		g, err := greeting.Await(w)
+		if err != nil {
+			return "", err
+		}
+		n, err := name.Await(w)
+		if err != nil {
+			return "", err
+		}
+		return g + ", " + n + "!", nil
+	})
+
+	got, err := promise.Await(mainWorker)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "Hello, world!"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestSpawn_selfDependency(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	self := make(chan workgraph.Promise[string], 1)
+	promise := workgraph.Spawn(mainWorker, func(w *workgraph.Worker) (string, error) {
+		return (<-self).Await(w)
+	})
+	self <- promise
+
+	_, err := promise.Await(mainWorker)
+	if _, ok := err.(workgraph.ErrSelfDependency); !ok {
+		t.Errorf("wrong error %v; want %T", err, workgraph.ErrSelfDependency{})
+	}
+}
+
+func TestSpawn_panic(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	promise := workgraph.Spawn(mainWorker, func(w *workgraph.Worker) (string, error) {
+		panic("oh no")
+	})
+
+	_, err := promise.Await(mainWorker)
+	panicErr, ok := err.(workgraph.ErrPanic)
+	if !ok {
+		t.Fatalf("wrong error %v; want %T", err, workgraph.ErrPanic{})
+	}
+	if got, want := panicErr.Value, "oh no"; got != want {
+		t.Errorf("wrong panic value %#v; want %#v", got, want)
+	}
+}
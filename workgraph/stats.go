@@ -0,0 +1,118 @@
+package workgraph
+
+import (
+	"sync/atomic"
+)
+
+// Counters is a snapshot of package-wide counters describing the work that
+// has passed through this package since the program started.
+//
+// These are intended for coarse-grained monitoring and progress reporting.
+// Each counter is updated atomically but the counters are not updated
+// together atomically, so a snapshot taken while work is in progress might
+// not be entirely self-consistent.
+type Counters struct {
+	// RequestsTotal is the number of requests that have been created,
+	// not including those created already resolved, such as by
+	// [Once.Seed]. This value only increases.
+	RequestsTotal int64
+
+	// ResolvedTotal is the number of requests that have been resolved,
+	// either explicitly by a worker or by this library to report a usage
+	// fault. This value only increases.
+	ResolvedTotal int64
+
+	// SelfDependencyTotal is the number of requests that have been resolved
+	// with [ErrSelfDependency]. This value only increases.
+	SelfDependencyTotal int64
+
+	// UnresolvedTotal is the number of requests that have been resolved
+	// with [ErrUnresolved] because their responsible worker was dropped.
+	// This value only increases.
+	UnresolvedTotal int64
+
+	// InFlight is the number of requests that have been created but not
+	// yet resolved. Unlike the other counters, this value decreases as
+	// requests are resolved.
+	InFlight int64
+}
+
+// Stats returns a snapshot of the current values of the package-wide
+// counters.
+func Stats() Counters {
+	return Counters{
+		RequestsTotal:       stats.requestsTotal.Load(),
+		ResolvedTotal:       stats.resolvedTotal.Load(),
+		SelfDependencyTotal: stats.selfDependencyTotal.Load(),
+		UnresolvedTotal:     stats.unresolvedTotal.Load(),
+		InFlight:            stats.inFlight.Load(),
+	}
+}
+
+// stats is where we track the package-wide counters reported by [Stats].
+var stats struct {
+	requestsTotal       atomic.Int64
+	resolvedTotal       atomic.Int64
+	selfDependencyTotal atomic.Int64
+	unresolvedTotal     atomic.Int64
+	inFlight            atomic.Int64
+}
+
+// countResolved updates the package-wide counters to reflect that a request
+// has just been resolved with the given error.
+func countResolved(err error) {
+	stats.resolvedTotal.Add(1)
+	stats.inFlight.Add(-1)
+	switch err.(type) {
+	case ErrSelfDependency:
+		stats.selfDependencyTotal.Add(1)
+	case ErrUnresolved:
+		stats.unresolvedTotal.Add(1)
+	}
+}
+
+// WaitStats describes how many workers waited for a particular request,
+// as returned by [Resolver.WaitStats].
+//
+// These statistics are collected only while enabled using
+// [SetWaitStatsEnabled], and so they describe only the awaits that began
+// while collection was enabled.
+type WaitStats struct {
+	// PeakWaiters is the largest number of workers that were blocked
+	// awaiting the request at the same time.
+	PeakWaiters int
+
+	// TotalWaiters is the total number of times a worker blocked awaiting
+	// the request. Awaits that returned immediately because the request
+	// was already resolved are not counted.
+	TotalWaiters int
+}
+
+// SetWaitStatsEnabled enables or disables the collection of the per-request
+// statistics returned by [Resolver.WaitStats].
+//
+// Collection is disabled by default to avoid the overhead on the slow path
+// of [Promise.Await].
+func SetWaitStatsEnabled(enabled bool) {
+	waitStatsEnabled.Store(enabled)
+}
+
+var waitStatsEnabled atomic.Bool
+
+// waitStatsCollector is the mutable representation of [WaitStats] in
+// a requestInner, which must be accessed only while holding the request's
+// mutex.
+type waitStatsCollector struct {
+	current int
+	stats   WaitStats
+}
+
+func (c *waitStatsCollector) enter() {
+	c.current++
+	c.stats.TotalWaiters++
+	c.stats.PeakWaiters = max(c.stats.PeakWaiters, c.current)
+}
+
+func (c *waitStatsCollector) exit() {
+	c.current--
+}
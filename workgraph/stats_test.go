@@ -0,0 +1,91 @@
+package workgraph_test
+
+import (
+	"testing"
+
+	"github.com/apparentlymart/go-workgraph/workgraph"
+)
+
+func TestStats_resolvedTotal(t *testing.T) {
+	w := workgraph.NewWorker()
+	resolvers := make([]workgraph.Resolver[int], 3)
+	for i := range resolvers {
+		resolvers[i], _ = workgraph.NewRequest[int](w)
+	}
+
+	before := workgraph.Stats()
+	for i, resolver := range resolvers {
+		resolver.ReportSuccess(w, i)
+	}
+	after := workgraph.Stats()
+
+	// The counters are package-wide and so could also be affected by
+	// stragglers from other tests, but we should see at least our own
+	// resolutions.
+	if got, want := after.ResolvedTotal-before.ResolvedTotal, int64(len(resolvers)); got < want {
+		t.Errorf("ResolvedTotal increased by %d; want at least %d", got, want)
+	}
+}
+
+func TestResolverWaitStats(t *testing.T) {
+	workgraph.SetWaitStatsEnabled(true)
+	defer workgraph.SetWaitStatsEnabled(false)
+
+	const waiters = 3
+	mainWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+	parked := make(chan struct{}, waiters)
+	done := make(chan struct{}, waiters)
+	for range waiters {
+		workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+			w.SetOnBlock(func(workgraph.RequestID) {
+				parked <- struct{}{}
+			})
+			promise.Await(w)
+			done <- struct{}{}
+		})
+	}
+	for range waiters {
+		<-parked
+	}
+	resolver.ReportSuccess(mainWorker, "Hello")
+	for range waiters {
+		<-done
+	}
+
+	got := resolver.WaitStats()
+	want := workgraph.WaitStats{
+		PeakWaiters:  waiters,
+		TotalWaiters: waiters,
+	}
+	if got != want {
+		t.Errorf("wrong stats\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestStats_failures(t *testing.T) {
+	before := workgraph.Stats()
+
+	w := workgraph.NewWorker()
+	_, selfPromise := workgraph.NewRequest[int](w)
+	selfPromise.Await(w)
+
+	otherWorker := workgraph.NewWorker()
+	_, unresolvedPromise := workgraph.NewRequest[int](otherWorker)
+	otherWorker.Close()
+	unresolvedPromise.Await(w)
+
+	after := workgraph.Stats()
+	if got, want := after.RequestsTotal-before.RequestsTotal, int64(2); got < want {
+		t.Errorf("RequestsTotal increased by %d; want at least %d", got, want)
+	}
+	if got, want := after.SelfDependencyTotal-before.SelfDependencyTotal, int64(1); got < want {
+		t.Errorf("SelfDependencyTotal increased by %d; want at least %d", got, want)
+	}
+	if got, want := after.UnresolvedTotal-before.UnresolvedTotal, int64(1); got < want {
+		t.Errorf("UnresolvedTotal increased by %d; want at least %d", got, want)
+	}
+	if after.InFlight < 0 {
+		t.Errorf("InFlight is negative: %d", after.InFlight)
+	}
+}
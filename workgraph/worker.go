@@ -1,7 +1,10 @@
 package workgraph
 
 import (
+	"context"
+	"fmt"
 	"runtime"
+	"sync/atomic"
 )
 
 // A Worker represents a specific linear codepath that will ultimately resolve
@@ -34,6 +37,33 @@ type Worker struct {
 	// it returns to notify the inner object once the outer object has been
 	// collected.
 	inner *workerInner
+
+	// onBlock is an optional callback set by [Worker.SetOnBlock]. This lives
+	// in the outer object rather than in workerInner because the callback
+	// might refer to the Worker itself, and the inner object must not keep
+	// the outer object live.
+	onBlock func(RequestID)
+
+	// skipSelfDependencyChecks is set by [Worker.SetSkipSelfDependencyChecks].
+	// It's atomic because [Delegate] can read it from a goroutine other than
+	// the one that owns the worker.
+	skipSelfDependencyChecks atomic.Bool
+
+	// cleanup is the handle for the cleanup function that drops the inner
+	// object once this object is garbage collected, which [Worker.Close]
+	// uses to detach it.
+	cleanup runtime.Cleanup
+
+	// awaitBudget is set by [NewWorkerWithAwaitBudget], and is nil for
+	// workers that may block any number of times.
+	awaitBudget *awaitBudget
+}
+
+// awaitBudget tracks how many more times a worker may block in
+// [Promise.Await] before it fails with [ErrAwaitBudgetExceeded].
+type awaitBudget struct {
+	limit     int
+	remaining atomic.Int64
 }
 
 // NewWorker allocates a new [Worker], optionally transferring responsibility
@@ -47,16 +77,119 @@ type Worker struct {
 // detected later if the previous responsible worker subsequently attempts to
 // resolve the request that was delegated.
 func NewWorker(delegatedResolvers ...ResolverContainer) *Worker {
+	return newWorker(nil, "", delegatedResolvers)
+}
+
+// NewNamedWorker is like [NewWorker] except that the new worker has the
+// given name.
+//
+// The name is used only for diagnostic purposes, such as in [Snapshot] and
+// in panic messages about incorrect use of the worker. It does not affect
+// the worker's identity, and so multiple workers may have the same name.
+func NewNamedWorker(name string, delegatedResolvers ...ResolverContainer) *Worker {
+	return newWorker(nil, name, delegatedResolvers)
+}
+
+// NewWorkerContext is like [NewWorker] except that the new worker is
+// associated with the given context.
+//
+// If the context is cancelled while the worker is blocked in [Promise.Await]
+// then that call returns early with the error returned by the context's Err
+// method, as if the worker had called [Promise.AwaitContext] with the same
+// context. Helpers that await using [Promise.Await], such as [AwaitAll], are
+// affected in the same way.
+//
+// Cancelling the context does not affect the requests that the worker is
+// responsible for. Use [WithWorkerContext] or [Worker.Cancel] to drop the
+// worker itself.
+//
+// Any children of the worker created by [NewChildWorker] inherit its
+// context, so that cancelling the context affects the whole subtree.
+func NewWorkerContext(ctx context.Context, delegatedResolvers ...ResolverContainer) *Worker {
+	ret := newWorker(nil, "", delegatedResolvers)
+	ret.inner.ctx = ctx
+	return ret
+}
+
+// NewWorkerWithAwaitBudget is like [NewWorker] except that the new worker
+// may block awaiting a promise at most n times.
+//
+// Once the budget is exhausted, any await that would block instead fails
+// immediately with [ErrAwaitBudgetExceeded]. Awaits of promises that are
+// already resolved don't block, and so don't count against the budget.
+//
+// This is intended as a debugging aid for finding workers that block far
+// more often than expected, such as because they await in a loop what
+// could instead be awaited all at once.
+func NewWorkerWithAwaitBudget(n int, delegatedResolvers ...ResolverContainer) *Worker {
+	ret := newWorker(nil, "", delegatedResolvers)
+	ret.awaitBudget = &awaitBudget{limit: n}
+	ret.awaitBudget.remaining.Store(int64(n))
+	return ret
+}
+
+// NewChildWorker is like [NewWorker] except that the new worker is a child of
+// the given parent worker.
+//
+// When a worker is dropped, its children are dropped along with it, and so
+// any requests that any of them are responsible for fail with
+// [ErrUnresolved]. This applies recursively to children of children. The
+// parent worker must therefore remain live until all of its children have
+// finished their work, which is most natural when each child's goroutine is
+// joined by its parent's goroutine before the parent's goroutine exits.
+//
+// A child worker can be dropped independently of its parent, without
+// affecting its parent.
+func NewChildWorker(parent *Worker, delegatedResolvers ...ResolverContainer) *Worker {
+	ret := newWorker(parent.inner, "", delegatedResolvers)
+	runtime.KeepAlive(parent)
+	return ret
+}
+
+// Delegate transfers responsibility for resolving some requests to an
+// existing worker, as an alternative to passing them to [NewWorker] when
+// creating a new worker.
+//
+// As with [NewWorker], the caller must previously have been responsible for
+// the given resolvers, and must not attempt to resolve them after calling
+// Delegate.
+//
+// Unlike a new worker, the given worker might already be awaiting a request
+// that depends on one of the delegated requests, in which case the
+// delegation completes a self-dependency cycle. Delegate detects that
+// situation and fails all of the requests in the cycle with
+// [ErrSelfDependency], just as if the worker had begun awaiting after the
+// delegation.
+func Delegate(to *Worker, delegatedResolvers ...ResolverContainer) {
+	for _, container := range delegatedResolvers {
+		for resolver := range container.ContainedResolvers() {
+			resolver.requestInner().setResponsibleWorker(to.inner)
+		}
+	}
+
+	// If the worker is currently awaiting something then it might now be
+	// awaiting something that depends on one of its new responsibilities.
+	// Any other cycle through the delegated requests would also involve
+	// the worker, and so this single check is sufficient.
+	if req := to.inner.awaiting.Load(); req != nil && !to.skipSelfDependencyChecks.Load() {
+		if selfDependency, _ := detectSelfDependency(req, to.inner, false); selfDependency {
+			resolveSelfDependency(req, to.inner, nil)
+		}
+	}
+	runtime.KeepAlive(to)
+}
+
+func newWorker(parent *workerInner, name string, delegatedResolvers []ResolverContainer) *Worker {
 	// The new "inner" is initially not awaiting any result.
-	newInner := newWorkerInner()
+	newInner := newWorkerInner(parent, name)
 
-	// We can safely transfer responsibility for all of the given result
-	// objects here without any self-dependency checking, because the new
+	// We can safely transfer responsibility for all of the given requests
+	// here without any self-dependency checking, because the new
 	// worker is initially not waiting for any results itself and so it
 	// cannot possibly participate in a self-dependency cycle.
 	for _, container := range delegatedResolvers {
-		for result := range container.ContainedResolvers() {
-			inner := result.resultInner()
+		for resolver := range container.ContainedResolvers() {
+			inner := resolver.requestInner()
 			inner.setResponsibleWorker(newInner)
 		}
 	}
@@ -67,10 +200,177 @@ func NewWorker(delegatedResolvers ...ResolverContainer) *Worker {
 	// The object we return has a cleanup function that notifies its associated
 	// inner once it gets collected, so we can force-unblock anything that's
 	// waiting on any results this result was responsible for.
-	runtime.AddCleanup(ret, (*workerInner).handleDropped, newInner)
+	ret.cleanup = runtime.AddCleanup(ret, (*workerInner).handleDropped, newInner)
 	return ret
 }
 
+// ID returns a unique identifier for the worker.
+//
+// This can be compared with the result of [Resolver.ResolvedBy] or
+// [Promise.ResolvedBy] to determine which worker resolved a request.
+func (w *Worker) ID() WorkerID {
+	return w.inner.WorkerID()
+}
+
+// Context returns the context associated with the worker by
+// [NewWorkerContext], or [context.Background] if the worker has no
+// associated context.
+func (w *Worker) Context() context.Context {
+	if ctx := w.inner.ctx; ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// ResponsibleFor returns the identifiers of all of the requests that the
+// worker is currently responsible for resolving, in no particular order.
+//
+// This is intended for verifying that a worker has resolved or delegated
+// all of its requests before it is dropped, such as in tests. The result
+// is a snapshot that does not change if the worker's responsibilities
+// change later.
+func (w *Worker) ResponsibleFor() []RequestID {
+	inner := w.inner
+	inner.mu.Lock()
+	ret := make([]RequestID, 0, len(inner.responsibleFor))
+	for req := range inner.responsibleFor {
+		ret = append(ret, req.RequestID())
+	}
+	inner.mu.Unlock()
+	return ret
+}
+
+// SetOnBlock registers a function to be called each time the worker is about
+// to block while awaiting a promise, replacing any function previously
+// registered. Passing nil removes any existing function.
+//
+// The function is called at most once per call to [Promise.Await], and only
+// if the request is not already resolved when the await begins. It receives
+// the identifier of the request being awaited, and it runs on the awaiting
+// goroutine without holding any of this package's locks. However, the worker
+// is already awaiting the request while the function runs, and so the
+// function must not use the worker to await anything else.
+func (w *Worker) SetOnBlock(f func(RequestID)) {
+	w.onBlock = f
+}
+
+// SetSkipSelfDependencyChecks controls whether the worker checks for
+// self-dependency before blocking in [Promise.Await].
+//
+// Skipping the check saves the cost of walking the chain of workers and
+// requests that the awaited request depends on, which can be significant for
+// deep chains. However, if the worker then awaits a request that does
+// (directly or indirectly) depend on something it's responsible for, the
+// affected workers deadlock instead of failing with [ErrSelfDependency].
+//
+// Use this only for workers whose dependencies are known to be acyclic by
+// construction. Other workers still check for self-dependency when awaiting
+// even if the chain they walk passes through a worker with checks disabled.
+func (w *Worker) SetSkipSelfDependencyChecks(skip bool) {
+	w.skipSelfDependencyChecks.Store(skip)
+}
+
+// SetCollectCyclesEagerly controls whether [Promise.Await] collects the
+// requests that it walks while checking for self-dependency, in case it
+// finds a cycle.
+//
+// By default the check walks the chain of dependencies without allocating
+// anything, and then if it finds a cycle it walks the chain a second time
+// to collect the requests to report in [ErrSelfDependency]. Enabling eager
+// collection avoids the second walk at the expense of allocating during
+// every await that blocks, which may be preferable when cycles are common,
+// such as during development. This setting affects all workers.
+func SetCollectCyclesEagerly(enabled bool) {
+	collectCyclesEagerly.Store(enabled)
+}
+
+// collectCyclesEagerly is set by [SetCollectCyclesEagerly].
+var collectCyclesEagerly atomic.Bool
+
+// Cancel immediately fails all of the requests that the worker is responsible
+// for with [ErrUnresolved], using the given error as its cause, as an
+// alternative to waiting for the garbage collector to notice that the worker
+// has been dropped.
+//
+// After calling Cancel the worker is considered to have been dropped, and so
+// it cannot take responsibility for any other requests: any request created
+// with the worker or delegated to it fails immediately. The children of the
+// worker, if any, are cancelled along with it.
+//
+// Cancel is safe to call concurrently with attempts to resolve the worker's
+// requests, in which case each request either keeps the result it was
+// resolved with or fails with the given error. Calling Cancel more than
+// once has no additional effect; the error from the first call is the one
+// used for all requests.
+func (w *Worker) Cancel(err error) {
+	w.inner.drop(err)
+}
+
+// Close immediately fails any requests that the worker is still responsible
+// for with [ErrUnresolved], exactly as would happen once the garbage
+// collector notices that the worker has been dropped, but deterministically.
+//
+// Callers should ideally arrange for each worker to be closed once its work
+// is complete, rather than relying on the garbage collector. Closing a
+// worker that has already resolved or delegated all of its requests has no
+// effect on any requests, and so a worker can be closed unconditionally
+// using defer.
+//
+// As with [Worker.Cancel], the worker is considered to have been dropped
+// after calling Close and so it cannot take responsibility for any other
+// requests, and its children are closed along with it. Calling Close more
+// than once has no additional effect.
+func (w *Worker) Close() {
+	w.cleanup.Stop()
+	w.inner.handleDropped()
+}
+
+// Reset prepares the worker to be reused for an independent phase of work,
+// as an alternative to allocating a new worker for each phase.
+//
+// Reset succeeds only if the worker has resolved or delegated all of the
+// requests it was responsible for, and otherwise returns
+// [ErrStillResponsible] without changing anything. It also fails with
+// [ErrWorkerDropped] if the worker was already dropped, since a dropped
+// worker can never be responsible for requests again.
+//
+// When successful, Reset discards the settings made by [Worker.SetOnBlock]
+// and [Worker.SetSkipSelfDependencyChecks] and refills the budget of a worker
+// created by [NewWorkerWithAwaitBudget], so that the worker behaves as if it
+// had just been created. The worker keeps its identity, name, and any child
+// workers.
+//
+// Reset panics if the worker is currently awaiting a promise, since that
+// implies that some other goroutine is still using the worker.
+func (w *Worker) Reset() error {
+	inner := w.inner
+	if inner.awaiting.Load() != nil {
+		panic(fmt.Sprintf("worker %s reset while awaiting a promise", inner))
+	}
+	inner.mu.Lock()
+	if inner.dropped {
+		cause := inner.dropCause
+		inner.mu.Unlock()
+		return ErrWorkerDropped{Cause: cause}
+	}
+	if len(inner.responsibleFor) != 0 {
+		ids := make([]RequestID, 0, len(inner.responsibleFor))
+		for req := range inner.responsibleFor {
+			ids = append(ids, req.RequestID())
+		}
+		inner.mu.Unlock()
+		return ErrStillResponsible{RequestIDs: ids}
+	}
+	inner.mu.Unlock()
+
+	w.onBlock = nil
+	w.skipSelfDependencyChecks.Store(false)
+	if budget := w.awaitBudget; budget != nil {
+		budget.remaining.Store(int64(budget.limit))
+	}
+	return nil
+}
+
 // WithNewSyncWorker is a helper wrapper around [NewWorker] for the common case
 // of associating a new worker with a new goroutine.
 //
@@ -81,3 +381,93 @@ func WithNewAsyncWorker(f func(*Worker), delegatedResults ...ResolverContainer)
 	worker := NewWorker(delegatedResults...)
 	go f(worker)
 }
+
+// GoWorker is like [WithNewAsyncWorker] except that it returns a
+// [WorkerHandle] that the caller can use to wait for f to return.
+//
+// If f panics then the panic is recovered and the worker is dropped
+// immediately, so that any requests it's still responsible for fail with
+// [ErrUnresolved] whose cause is an [ErrPanic] describing the panic. The
+// panic is then raised again by [WorkerHandle.Wait], or returned as an error
+// by [WorkerHandle.Err], so that it's reported at the point where the caller
+// joins the worker rather than crashing the program. A caller of GoWorker
+// should therefore always eventually call one of those methods.
+func GoWorker(f func(*Worker), delegatedResults ...ResolverContainer) *WorkerHandle {
+	worker := NewWorker(delegatedResults...)
+	handle := &WorkerHandle{
+		done: make(chan struct{}),
+	}
+	go func() {
+		defer close(handle.done)
+		_, err := callRecovering(worker, func(w *Worker) (struct{}, error) {
+			f(w)
+			return struct{}{}, nil
+		})
+		if err != nil {
+			handle.panicErr = err
+			worker.inner.drop(err)
+		}
+	}()
+	return handle
+}
+
+// WorkerHandle represents a function running asynchronously on its own
+// worker, as started by [GoWorker].
+type WorkerHandle struct {
+	done chan struct{}
+
+	// panicErr is an [ErrPanic] if the function panicked, and is written
+	// only before done is closed.
+	panicErr error
+}
+
+// Wait blocks until the function associated with the handle has returned.
+//
+// Waiting for a handle is not the same as awaiting a promise, and so it does
+// not participate in self-dependency detection. Callers should prefer to
+// await the promises that the function is responsible for when they need
+// its results, and use Wait only to ensure that the function has finished.
+//
+// If the function panicked then Wait panics with an [ErrPanic] value
+// describing the original panic, including the stack trace of the
+// goroutine where it occurred.
+func (h *WorkerHandle) Wait() {
+	if err := h.Err(); err != nil {
+		panic(err)
+	}
+}
+
+// Err is like [WorkerHandle.Wait] except that if the function panicked then
+// it returns an [ErrPanic] describing the panic, rather than panicking.
+// Otherwise it returns nil.
+func (h *WorkerHandle) Err() error {
+	<-h.done
+	return h.panicErr
+}
+
+// WithWorkerContext calls f with a new [Worker] whose responsibilities are
+// tied to the given context, and returns once f returns.
+//
+// If the context is cancelled while f is running then the worker is
+// cancelled as if by calling [Worker.Cancel] with the context's error, such
+// as [context.Canceled]. The worker is associated with the context as if
+// created by [NewWorkerContext], and so its awaits also return early.
+//
+// Once f returns the worker is considered to have been dropped, and so any
+// requests that it is still responsible for immediately fail with
+// [ErrUnresolved] without waiting for the garbage collector. The given
+// function must therefore delegate or resolve all of its requests before
+// returning, and must not retain the worker after it returns.
+//
+// This is intended for use at the boundary between a context-aware caller,
+// such as an HTTP request handler, and a workgraph-based computation.
+func WithWorkerContext(ctx context.Context, f func(*Worker)) {
+	worker := NewWorkerContext(ctx)
+	inner := worker.inner
+	stop := context.AfterFunc(ctx, func() {
+		inner.drop(ctx.Err())
+	})
+	defer stop()
+	defer inner.handleDropped()
+	f(worker)
+}
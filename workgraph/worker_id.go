@@ -0,0 +1,54 @@
+package workgraph
+
+import (
+	"fmt"
+	"log/slog"
+	"weak"
+)
+
+// WorkerID represents an opaque but comparable unique identifier for a
+// worker, which may or may not still be live.
+//
+// Use [Worker.ID] to find the identity of a particular worker.
+type WorkerID struct {
+	// As with [RequestID], we use a weak pointer here because we only care
+	// about pointer identity, and so a WorkerID does not prevent the
+	// associated workerInner from being garbage collected.
+	ptr weak.Pointer[workerInner]
+}
+
+// Equal returns true if other is the same [WorkerID] as the receiver.
+//
+// This is equivalent to using the "==" operator to compare two values, but
+// is implemented here to work better with libraries like Google's "go-cmp"
+// which try to perform deep comparison when no Equal method is present.
+func (wid WorkerID) Equal(other WorkerID) bool {
+	return wid == other
+}
+
+// String returns a human-oriented string representation of the worker ID.
+//
+// This is intended for debug messages only. Do not use the result as a unique
+// key for a [WorkerID]; this type is comparable so it can act as its own
+// unique key.
+func (wid WorkerID) String() string {
+	return fmt.Sprintf("%p", wid.ptr.Value())
+}
+
+func (wid WorkerID) GoString() string {
+	return fmt.Sprintf("workgraph.WorkerID(%s)", wid.String())
+}
+
+// LogValue implements [slog.LogValuer] by returning the same string as
+// [WorkerID.String], so that identifiers appear as simple strings in structured
+// logs.
+func (wid WorkerID) LogValue() slog.Value {
+	return slog.StringValue(wid.String())
+}
+
+// MarshalText implements [encoding.TextMarshaler] by returning the same
+// string as [WorkerID.String], so that identifiers can be included in
+// serialized debug output such as from [Snapshot].
+func (wid WorkerID) MarshalText() ([]byte, error) {
+	return []byte(wid.String()), nil
+}
@@ -1,8 +1,11 @@
 package workgraph
 
 import (
+	"context"
+	"fmt"
 	"sync"
 	"sync/atomic"
+	"weak"
 )
 
 // workerInner is the real representation of a worker, which participates
@@ -17,26 +20,200 @@ type workerInner struct {
 	// between a worker and the result it's currently awaiting, if any.
 	//
 	// This is an atomic pointer so we can perform the first pass of
-	// self-dependency checking without acquiring any locks.
+	// self-dependency checking without acquiring any locks. It's only
+	// changed by [workerInner.beginAwait] and [workerInner.endAwait], while
+	// holding mu.
 	awaiting atomic.Pointer[requestInner]
 
+	// awaitingCount is the number of goroutines currently awaiting the
+	// request in awaiting using this worker, so that the last of them to
+	// finish can reset it. It's guarded by mu.
+	awaitingCount int
+
 	responsibleFor map[*requestInner]struct{}
 	mu             sync.Mutex
+
+	// parent and children represent the tree of workers created using
+	// [NewChildWorker]. When a worker is dropped, all of its children are
+	// dropped too. parent is nil for a worker created by [NewWorker].
+	parent   *workerInner
+	children map[*workerInner]struct{}
+
+	// dropped is set once the worker has been dropped, after which it's
+	// no longer able to take responsibility for any requests. dropCause
+	// is the error passed to [workerInner.drop] when it was dropped.
+	dropped   bool
+	dropCause error
+
+	// name is an optional label given to [NewNamedWorker], used only in
+	// diagnostic messages.
+	name string
+
+	// ctx is the context given to [NewWorkerContext], or inherited from the
+	// parent worker, which [Promise.Await] respects while blocking. This is
+	// nil if the worker has no context. It must not change once the worker
+	// has been returned to the caller.
+	ctx context.Context
 }
 
-func newWorkerInner() *workerInner {
-	return &workerInner{
+func newWorkerInner(parent *workerInner, name string) *workerInner {
+	ret := &workerInner{
 		responsibleFor: make(map[*requestInner]struct{}),
+		parent:         parent,
+		name:           name,
+	}
+	if parent != nil {
+		ret.ctx = parent.ctx
+	}
+	liveWorkers.Store(ret, struct{}{})
+	if parent != nil {
+		parent.mu.Lock()
+		parentDropped := parent.dropped
+		if !parentDropped {
+			if parent.children == nil {
+				parent.children = make(map[*workerInner]struct{})
+			}
+			parent.children[ret] = struct{}{}
+		}
+		parent.mu.Unlock()
+		if parentDropped {
+			// A child of an already-dropped worker begins life dropped.
+			ret.handleDropped()
+		}
+	}
+	return ret
+}
+
+func (wi *workerInner) WorkerID() WorkerID {
+	return WorkerID{
+		ptr: weak.Make(wi),
 	}
 }
 
+// String returns a description of the worker for use in panic messages,
+// including its name if it has one.
+func (wi *workerInner) String() string {
+	if wi != nil && wi.name != "" {
+		return fmt.Sprintf("%p (%q)", wi, wi.name)
+	}
+	return fmt.Sprintf("%p", wi)
+}
+
+// errConcurrentAwait returns the value to panic with when the worker is
+// asked to await the given request while it's already awaiting another.
+func (wi *workerInner) errConcurrentAwait(requested *requestInner) ErrConcurrentAwait {
+	return ErrConcurrentAwait{
+		Worker:    wi.String(),
+		Awaiting:  wi.awaiting.Load().RequestID(),
+		Requested: requested.RequestID(),
+	}
+}
+
+// beginAwait records that the worker is awaiting the given request.
+//
+// If the worker is already awaiting that same request from another
+// goroutine then this goroutine joins that wait, and beginAwait returns
+// joined as true. It returns ok as false if the worker is already awaiting
+// a different request, in which case it doesn't change anything.
+//
+// Each successful call must be paired with a call to
+// [workerInner.endAwait] once the wait is over.
+func (wi *workerInner) beginAwait(ri *requestInner) (joined, ok bool) {
+	wi.mu.Lock()
+	defer wi.mu.Unlock()
+	switch wi.awaiting.Load() {
+	case nil:
+		wi.awaiting.Store(ri)
+		wi.awaitingCount = 1
+		return false, true
+	case ri:
+		wi.awaitingCount++
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+// endAwait undoes an earlier successful call to [workerInner.beginAwait],
+// resetting the awaited request once every goroutine awaiting it is done.
+func (wi *workerInner) endAwait(ri *requestInner) {
+	wi.mu.Lock()
+	if wi.awaiting.Load() != ri {
+		wi.mu.Unlock()
+		panic(wi.errConcurrentAwait(ri))
+	}
+	wi.awaitingCount--
+	if wi.awaitingCount == 0 {
+		wi.awaiting.Store(nil)
+	}
+	wi.mu.Unlock()
+}
+
 func (wi *workerInner) handleDropped() {
 	// If the caller-facing handle to this worker is dropped then any
 	// requests this worker was responsible cannot be resolved, so
 	// we'll force them to fail here.
+	wi.drop(nil)
+}
+
+// drop marks the worker as dropped and force-fails all of the requests it's
+// responsible for, and then does the same for all of its children.
+//
+// The requests fail with [ErrUnresolved] using the given cause, which may be
+// nil. Only the first call to drop decides the cause, and any subsequent
+// calls just force-fail any requests that the worker has somehow become
+// responsible for in the meantime.
+func (wi *workerInner) drop(cause error) {
+	wi.mu.Lock()
+	if !wi.dropped {
+		wi.dropped = true
+		wi.dropCause = cause
+	}
+	children := wi.children
+	wi.children = nil
+	wi.mu.Unlock()
+	liveWorkers.Delete(wi)
+
+	wi.failResponsibilities(wi.dropError)
+
+	// A dropped worker's children are dropped too, recursively, so that
+	// they can't outlive the worker that started them.
+	for child := range children {
+		child.drop(cause)
+	}
+	if wi.parent != nil {
+		wi.parent.mu.Lock()
+		delete(wi.parent.children, wi)
+		wi.parent.mu.Unlock()
+	}
+}
+
+// dropError returns the error that the given request must fail with because
+// the worker was dropped. This must be called only after the worker has
+// been marked as dropped.
+func (wi *workerInner) dropError(req *requestInner) error {
+	return ErrUnresolved{
+		RequestID: req.RequestID(),
+		Cause:     wi.dropCause,
+	}
+}
+
+// failResponsibilities force-resolves all of the requests that the worker
+// is currently responsible for as usage faults, using makeErr to decide the
+// error for each one.
+func (wi *workerInner) failResponsibilities(makeErr func(req *requestInner) error) {
+	// We take a snapshot of the requests while holding our lock but then
+	// resolve them only after releasing it, because resolution acquires
+	// the lock of each request and [requestInner.resolveExplicit] acquires
+	// those two locks in the opposite order.
 	wi.mu.Lock()
+	reqs := make([]*requestInner, 0, len(wi.responsibleFor))
 	for req := range wi.responsibleFor {
-		req.resolveUsageFault(ErrUnresolved{RequestID: req.ResultID()})
+		reqs = append(reqs, req)
 	}
 	wi.mu.Unlock()
+
+	for _, req := range reqs {
+		req.resolveUsageFault(makeErr(req))
+	}
 }
@@ -0,0 +1,625 @@
+package workgraph_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/apparentlymart/go-workgraph/workgraph"
+)
+
+func TestWithWorkerContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errs := make(chan error, 1)
+	workgraph.WithWorkerContext(ctx, func(w *workgraph.Worker) {
+		_, promise := workgraph.NewRequest[string](w)
+		workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+			_, err := promise.Await(w)
+			errs <- err
+		})
+
+		// Cancelling the context should cause the request that our worker
+		// is responsible for to fail, unblocking the other worker.
+		cancel()
+		if err := <-errs; !errors.Is(err, context.Canceled) {
+			t.Errorf("wrong error %v; want %v", err, context.Canceled)
+		}
+	})
+}
+
+func TestWithWorkerContext_workerContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	workgraph.WithWorkerContext(ctx, func(w *workgraph.Worker) {
+		if got := w.Context(); got != ctx {
+			t.Errorf("wrong worker context %v; want %v", got, ctx)
+		}
+
+		// The worker's awaits return early once the context is cancelled,
+		// even for a request that belongs to some other worker.
+		otherWorker := workgraph.NewWorker()
+		defer runtime.KeepAlive(otherWorker)
+		_, promise := workgraph.NewRequest[string](otherWorker)
+		time.AfterFunc(10*time.Millisecond, cancel)
+		if _, err := promise.Await(w); err != context.Canceled {
+			t.Errorf("wrong error %v; want %v", err, context.Canceled)
+		}
+	})
+}
+
+func TestWithWorkerContext_return(t *testing.T) {
+	var promise workgraph.Promise[string]
+	workgraph.WithWorkerContext(context.Background(), func(w *workgraph.Worker) {
+		_, promise = workgraph.NewRequest[string](w)
+	})
+
+	// The worker was dropped as soon as the function returned, and so the
+	// request it was responsible for has already failed.
+	_, err := promise.Await(workgraph.NewWorker())
+	if _, ok := err.(workgraph.ErrUnresolved); !ok {
+		t.Errorf("wrong error %v; want %T", err, workgraph.ErrUnresolved{})
+	}
+}
+
+func TestWorkerSetOnBlock(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+
+	blocked := make(chan workgraph.RequestID, 1)
+	mainWorker.SetOnBlock(func(id workgraph.RequestID) {
+		blocked <- id
+	})
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		// We'll wait until the main worker reports that it's blocking
+		// before we resolve, so that it must take the slow path.
+		<-blocked
+		resolver.ReportSuccess(w, "Hello")
+	}, resolver)
+
+	if _, err := promise.Await(mainWorker); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Now that the promise is resolved, awaiting it again takes the fast
+	// path and so should not call the function again.
+	mainWorker.SetOnBlock(func(id workgraph.RequestID) {
+		t.Errorf("OnBlock function called for resolved request %s", id)
+	})
+	if _, err := promise.Await(mainWorker); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestWorkerSetSkipSelfDependencyChecks(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	mainWorker.SetSkipSelfDependencyChecks(true)
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		resolver.ReportSuccess(w, "Hello")
+	}, resolver)
+
+	got, err := promise.Await(mainWorker)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "Hello"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func BenchmarkDeepChain(b *testing.B) {
+	const depth = 200
+
+	for _, skip := range []bool{false, true} {
+		name := "checked"
+		if skip {
+			name = "unchecked"
+		}
+		b.Run(name, func(b *testing.B) {
+			for range b.N {
+				runDeepChain(depth, skip)
+			}
+		})
+	}
+}
+
+// runDeepChain builds a chain of workers that each await the result of the
+// next, starting from the end of the chain so that each new await must walk
+// the entire chain built so far when checking for self-dependency.
+func runDeepChain(depth int, skip bool) {
+	mainWorker := workgraph.NewWorker()
+	resolvers := make([]workgraph.Resolver[int], depth)
+	promises := make([]workgraph.Promise[int], depth)
+	for i := range resolvers {
+		resolvers[i], promises[i] = workgraph.NewRequest[int](mainWorker)
+	}
+
+	release := make(chan struct{})
+	parked := make(chan struct{})
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		<-release
+		resolvers[depth-1].ReportSuccess(w, 0)
+	}, resolvers[depth-1])
+	for i := depth - 2; i >= 0; i-- {
+		workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+			w.SetSkipSelfDependencyChecks(skip)
+			w.SetOnBlock(func(workgraph.RequestID) {
+				parked <- struct{}{}
+			})
+			v, err := promises[i+1].Await(w)
+			resolvers[i].Report(w, v+1, err)
+		}, resolvers[i])
+		<-parked
+	}
+	close(release)
+	promises[0].Await(mainWorker)
+}
+
+func TestNewChildWorker_cascadingDrop(t *testing.T) {
+	var childPromise, grandchildPromise workgraph.Promise[string]
+	var child, grandchild *workgraph.Worker
+	workgraph.WithWorkerContext(context.Background(), func(w *workgraph.Worker) {
+		child = workgraph.NewChildWorker(w)
+		grandchild = workgraph.NewChildWorker(child)
+		_, childPromise = workgraph.NewRequest[string](child)
+		_, grandchildPromise = workgraph.NewRequest[string](grandchild)
+	})
+
+	// The parent worker was dropped when the function returned, and so
+	// all of its descendents were dropped too even though we're still
+	// holding references to them.
+	for name, promise := range map[string]workgraph.Promise[string]{
+		"child":      childPromise,
+		"grandchild": grandchildPromise,
+	} {
+		_, err := promise.Await(workgraph.NewWorker())
+		if _, ok := err.(workgraph.ErrUnresolved); !ok {
+			t.Errorf("wrong error for %s %v; want %T", name, err, workgraph.ErrUnresolved{})
+		}
+	}
+
+	// Requests created by a dropped worker fail immediately.
+	_, promise := workgraph.NewRequest[string](grandchild)
+	_, err := promise.Await(workgraph.NewWorker())
+	if _, ok := err.(workgraph.ErrUnresolved); !ok {
+		t.Errorf("wrong error for new request %v; want %T", err, workgraph.ErrUnresolved{})
+	}
+	runtime.KeepAlive(child)
+}
+
+func TestWorkerCancel(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	cancelled := workgraph.NewWorker()
+	_, promise1 := workgraph.NewRequest[string](cancelled)
+	_, promise2 := workgraph.NewRequest[string](cancelled)
+
+	errShutdown := errors.New("shutting down")
+	cancelled.Cancel(errShutdown)
+	cancelled.Cancel(errors.New("ignored")) // only the first cause is used
+
+	// Requests created after cancellation fail immediately too.
+	_, promise3 := workgraph.NewRequest[string](cancelled)
+
+	for i, promise := range []workgraph.Promise[string]{promise1, promise2, promise3} {
+		_, err := promise.Await(mainWorker)
+		if !errors.Is(err, errShutdown) {
+			t.Errorf("wrong error for promise %d: %v; want %v", i, err, errShutdown)
+		}
+	}
+}
+
+func TestWorkerResponsibleFor(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolver1, _ := workgraph.NewRequest[string](mainWorker)
+	resolver2, _ := workgraph.NewRequest[string](mainWorker)
+
+	got := mainWorker.ResponsibleFor()
+	want := []workgraph.RequestID{resolver1.RequestID(), resolver2.RequestID()}
+	if !sameRequestIDs(got, want) {
+		t.Errorf("wrong initial responsibilities %s; want %s", got, want)
+	}
+
+	// Delegating a request moves the responsibility to the new worker.
+	otherWorker := workgraph.NewWorker(resolver2)
+	if got, want := mainWorker.ResponsibleFor(), []workgraph.RequestID{resolver1.RequestID()}; !sameRequestIDs(got, want) {
+		t.Errorf("wrong responsibilities after delegation %s; want %s", got, want)
+	}
+	if got, want := otherWorker.ResponsibleFor(), []workgraph.RequestID{resolver2.RequestID()}; !sameRequestIDs(got, want) {
+		t.Errorf("wrong responsibilities for new worker %s; want %s", got, want)
+	}
+
+	resolver1.ReportSuccess(mainWorker, "done")
+	if got := mainWorker.ResponsibleFor(); len(got) != 0 {
+		t.Errorf("unexpected responsibilities after resolving %s", got)
+	}
+	runtime.KeepAlive(otherWorker)
+}
+
+// sameRequestIDs returns true if a and b contain the same request IDs,
+// regardless of order.
+func sameRequestIDs(a, b []workgraph.RequestID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, id := range a {
+		if !slices.Contains(b, id) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDelegate(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	otherWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+
+	workgraph.Delegate(otherWorker, resolver)
+	if got := mainWorker.ResponsibleFor(); len(got) != 0 {
+		t.Errorf("unexpected responsibilities for original worker %s", got)
+	}
+	if got, want := otherWorker.ResponsibleFor(), []workgraph.RequestID{resolver.RequestID()}; !sameRequestIDs(got, want) {
+		t.Errorf("wrong responsibilities for new worker %s; want %s", got, want)
+	}
+
+	// Now that mainWorker is no longer responsible, it can await the
+	// request without self-dependency.
+	go resolver.ReportSuccess(otherWorker, "Hello")
+	got, err := promise.Await(mainWorker)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "Hello"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestDelegate_selfDependency(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	otherWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](otherWorker)
+
+	blocked := make(chan struct{})
+	mainWorker.SetOnBlock(func(workgraph.RequestID) {
+		close(blocked)
+	})
+	result := make(chan error)
+	go func() {
+		_, err := promise.Await(mainWorker)
+		result <- err
+	}()
+	<-blocked
+
+	// mainWorker is already waiting for the request, so delegating the
+	// request to it creates a self-dependency.
+	workgraph.Delegate(mainWorker, resolver)
+	err := <-result
+	if _, ok := err.(workgraph.ErrSelfDependency); !ok {
+		t.Errorf("wrong error %v; want %T", err, workgraph.ErrSelfDependency{})
+	}
+}
+
+func TestDelegate_concurrentSetSkipSelfDependencyChecks(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	otherWorker := workgraph.NewWorker()
+
+	// Delegating from another goroutine while the worker's own goroutine
+	// changes its settings must not race, which the race detector checks.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			resolver, _ := workgraph.NewRequest[string](otherWorker)
+			workgraph.Delegate(mainWorker, resolver)
+		}
+	}()
+	for i := range 100 {
+		mainWorker.SetSkipSelfDependencyChecks(i%2 == 0)
+	}
+	<-done
+}
+
+func TestDelegate_oppositeDirections(t *testing.T) {
+	workerA := workgraph.NewWorker()
+	workerB := workgraph.NewWorker()
+
+	// Delegating between the same two workers in both directions at once
+	// must not deadlock. The goroutines must actually run in parallel for
+	// that to be likely to show up.
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(8))
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 10000 {
+				resolver, _ := workgraph.NewRequest[string](workerA)
+				workgraph.Delegate(workerB, resolver)
+				resolver.ReportSuccess(workerB, "")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 10000 {
+				resolver, _ := workgraph.NewRequest[string](workerB)
+				workgraph.Delegate(workerA, resolver)
+				resolver.ReportSuccess(workerA, "")
+			}
+		}()
+	}
+	wg.Wait()
+	if got := workerA.ResponsibleFor(); len(got) != 0 {
+		t.Errorf("workerA still responsible for %s", got)
+	}
+	if got := workerB.ResponsibleFor(); len(got) != 0 {
+		t.Errorf("workerB still responsible for %s", got)
+	}
+}
+
+func TestWorkerResponsibleFor_usageFault(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	_, promise := workgraph.NewRequest[string](mainWorker)
+	otherWorker := workgraph.NewWorker()
+	workgraph.NewRequest[string](otherWorker)
+
+	// A request failed by this library is no longer the responsibility
+	// of the worker, whether it failed because of a self-dependency...
+	promise.Await(mainWorker)
+	if got := mainWorker.ResponsibleFor(); len(got) != 0 {
+		t.Errorf("unexpected responsibilities after self-dependency %s", got)
+	}
+
+	// ...or because the worker was dropped.
+	otherWorker.Cancel(nil)
+	if got := otherWorker.ResponsibleFor(); len(got) != 0 {
+		t.Errorf("unexpected responsibilities after cancellation %s", got)
+	}
+}
+
+func TestWorkerReset(t *testing.T) {
+	w := workgraph.NewWorker()
+	w.SetSkipSelfDependencyChecks(true)
+	resolver, promise := workgraph.NewRequest[string](w)
+
+	err := w.Reset()
+	stillErr, ok := err.(workgraph.ErrStillResponsible)
+	if !ok {
+		t.Fatalf("wrong error %v; want %T", err, stillErr)
+	}
+	if got, want := stillErr.RequestIDs, []workgraph.RequestID{resolver.RequestID()}; !sameRequestIDs(got, want) {
+		t.Errorf("wrong request ids %s; want %s", got, want)
+	}
+
+	resolver.ReportSuccess(w, "done")
+	if err := w.Reset(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The reset worker checks for self-dependency again, because Reset
+	// discarded the earlier setting.
+	_, promise = workgraph.NewRequest[string](w)
+	if _, err := promise.Await(w); !errors.Is(err, workgraph.ErrSelfDependency{}) {
+		t.Errorf("wrong error %v; want self-dependency error", err)
+	}
+
+	w.Cancel(errors.New("cancelled"))
+	if _, ok := w.Reset().(workgraph.ErrWorkerDropped); !ok {
+		t.Errorf("Reset succeeded for a dropped worker")
+	}
+}
+
+func TestSetCollectCyclesEagerly(t *testing.T) {
+	workgraph.SetCollectCyclesEagerly(true)
+	defer workgraph.SetCollectCyclesEagerly(false)
+
+	mainWorker := workgraph.NewWorker()
+	resolver1, promise1 := workgraph.NewRequest[string](mainWorker)
+	resolver2, promise2 := workgraph.NewRequest[string](mainWorker)
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		val, err := promise2.Await(w)
+		resolver1.Report(w, val, err)
+	}, resolver1)
+
+	// The result is the same as without eager collection.
+	_, err := promise1.Await(mainWorker)
+	selfDepErr, ok := err.(workgraph.ErrSelfDependency)
+	if !ok {
+		t.Fatalf("wrong error %v; want %T", err, selfDepErr)
+	}
+	want := []workgraph.RequestID{resolver1.RequestID(), resolver2.RequestID()}
+	if !sameRequestIDs(selfDepErr.RequestIDs, want) {
+		t.Errorf("wrong request ids %s; want %s", selfDepErr.RequestIDs, want)
+	}
+}
+
+func TestGoWorker(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+	var finished atomic.Bool
+	handle := workgraph.GoWorker(func(w *workgraph.Worker) {
+		resolver.ReportSuccess(w, "Hello")
+		finished.Store(true)
+	}, resolver)
+	handle.Wait()
+
+	if !finished.Load() {
+		t.Error("Wait returned before the function finished")
+	}
+	got, err := promise.Await(mainWorker)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "Hello"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestGoWorker_panic(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+	handle := workgraph.GoWorker(func(w *workgraph.Worker) {
+		panic("oh no")
+	}, resolver)
+
+	err := handle.Err()
+	panicErr, ok := err.(workgraph.ErrPanic)
+	if !ok {
+		t.Fatalf("wrong error type %T; want %T", err, panicErr)
+	}
+	if got, want := panicErr.Value, any("oh no"); got != want {
+		t.Errorf("wrong panic value %#v; want %#v", got, want)
+	}
+
+	// The worker is dropped as soon as it panics, so the request it was
+	// responsible for fails immediately with the panic as its cause.
+	_, err = promise.Await(mainWorker)
+	unresolvedErr, ok := err.(workgraph.ErrUnresolved)
+	if !ok {
+		t.Fatalf("wrong error type %T; want %T", err, unresolvedErr)
+	}
+	if _, ok := unresolvedErr.Cause.(workgraph.ErrPanic); !ok {
+		t.Errorf("wrong cause %T; want %T", unresolvedErr.Cause, panicErr)
+	}
+
+	defer func() {
+		if _, ok := recover().(workgraph.ErrPanic); !ok {
+			t.Errorf("Wait did not re-panic with %T", panicErr)
+		}
+	}()
+	handle.Wait()
+}
+
+func TestNewWorkerContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := workgraph.NewWorkerContext(ctx)
+	child := workgraph.NewChildWorker(w)
+	otherWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](otherWorker)
+	defer resolver.ReportSuccess(otherWorker, "")
+
+	if got := w.Context(); got != ctx {
+		t.Errorf("wrong context %v; want %v", got, ctx)
+	}
+	if got := workgraph.NewWorker().Context(); got != context.Background() {
+		t.Errorf("wrong context for worker without context %v; want %v", got, context.Background())
+	}
+
+	blocked := make(chan struct{})
+	child.SetOnBlock(func(workgraph.RequestID) {
+		close(blocked)
+	})
+	go func() {
+		<-blocked
+		cancel()
+	}()
+
+	// The child inherits its parent's context, so its Await returns once
+	// that context is cancelled.
+	_, err := promise.Await(child)
+	if err != context.Canceled {
+		t.Errorf("wrong error %v; want %v", err, context.Canceled)
+	}
+	_, err = promise.Await(w)
+	if err != context.Canceled {
+		t.Errorf("wrong error %v; want %v", err, context.Canceled)
+	}
+	if promise.IsResolved() {
+		t.Error("request was resolved by context cancellation")
+	}
+}
+
+func TestNewWorker_mixedResultTypes(t *testing.T) {
+	mainWorker := workgraph.NewWorker()
+	intResolver, intPromise := workgraph.NewRequest[int](mainWorker)
+	stringResolver, stringPromise := workgraph.NewRequest[string](mainWorker)
+
+	// Resolvers for requests of different result types can all be
+	// delegated in a single call, because they are all ResolverContainers.
+	workgraph.WithNewAsyncWorker(func(w *workgraph.Worker) {
+		intResolver.ReportSuccess(w, 5)
+		stringResolver.ReportSuccess(w, "five")
+	}, intResolver, stringResolver)
+
+	gotInt, err := intPromise.Await(mainWorker)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	gotString, err := stringPromise.Await(mainWorker)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if gotInt != 5 || gotString != "five" {
+		t.Errorf("wrong results %d and %q; want 5 and %q", gotInt, gotString, "five")
+	}
+}
+
+func TestNewWorkerWithAwaitBudget(t *testing.T) {
+	w := workgraph.NewWorkerWithAwaitBudget(1)
+	otherWorker := workgraph.NewWorker()
+	resolver1, promise1 := workgraph.NewRequest[string](otherWorker)
+	resolver2, promise2 := workgraph.NewRequest[string](otherWorker)
+	defer resolver2.ReportSuccess(otherWorker, "")
+
+	w.SetOnBlock(func(workgraph.RequestID) {
+		go resolver1.ReportSuccess(otherWorker, "Hello")
+	})
+	if _, err := promise1.Await(w); err != nil {
+		t.Fatalf("unexpected error for first await: %s", err)
+	}
+	// Awaiting an already-resolved promise doesn't spend any budget.
+	if _, err := promise1.Await(w); err != nil {
+		t.Fatalf("unexpected error for resolved promise: %s", err)
+	}
+
+	_, err := promise2.Await(w)
+	budgetErr, ok := err.(workgraph.ErrAwaitBudgetExceeded)
+	if !ok {
+		t.Fatalf("wrong error %v; want %T", err, budgetErr)
+	}
+	if !budgetErr.RequestID.Equal(resolver2.RequestID()) {
+		t.Errorf("wrong request ID %s; want %s", budgetErr.RequestID, resolver2.RequestID())
+	}
+	if got, want := budgetErr.Budget, 1; got != want {
+		t.Errorf("wrong budget %d; want %d", got, want)
+	}
+}
+
+func TestWorkerReset_awaitBudget(t *testing.T) {
+	w := workgraph.NewWorkerWithAwaitBudget(1)
+	otherWorker := workgraph.NewWorker()
+	resolver1, promise1 := workgraph.NewRequest[string](otherWorker)
+	resolver2, promise2 := workgraph.NewRequest[string](otherWorker)
+
+	w.SetOnBlock(func(workgraph.RequestID) {
+		go resolver1.ReportSuccess(otherWorker, "Hello")
+	})
+	if _, err := promise1.Await(w); err != nil {
+		t.Fatalf("unexpected error for first await: %s", err)
+	}
+	if _, err := promise2.Await(w); !errors.As(err, new(workgraph.ErrAwaitBudgetExceeded)) {
+		t.Fatalf("wrong error %v; want budget exceeded", err)
+	}
+
+	// Reset refills the budget, so the worker can block once more.
+	if err := w.Reset(); err != nil {
+		t.Fatalf("unexpected error from Reset: %s", err)
+	}
+	w.SetOnBlock(func(workgraph.RequestID) {
+		go resolver2.ReportSuccess(otherWorker, "World")
+	})
+	got, err := promise2.Await(w)
+	if err != nil {
+		t.Fatalf("unexpected error after Reset: %s", err)
+	}
+	if want := "World"; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
@@ -0,0 +1,78 @@
+// Package workgraphmetrics exposes the package-wide counters reported by
+// [workgraph.Stats] as Prometheus metrics.
+//
+// This is a separate module so that programs that use package workgraph
+// without Prometheus don't need to depend on the Prometheus client library.
+package workgraphmetrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/apparentlymart/go-workgraph/workgraph"
+)
+
+// Collector is a [prometheus.Collector] that reports the counters from
+// [workgraph.Stats] each time it's collected.
+//
+// The counters are package-wide, so a program should register at most one
+// collector with any particular registry.
+type Collector struct {
+	requestsTotal       *prometheus.Desc
+	resolvedTotal       *prometheus.Desc
+	selfDependencyTotal *prometheus.Desc
+	unresolvedTotal     *prometheus.Desc
+	inFlight            *prometheus.Desc
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector returns a new [Collector], ready to be registered with a
+// Prometheus registry.
+func NewCollector() *Collector {
+	return &Collector{
+		requestsTotal: prometheus.NewDesc(
+			"workgraph_requests_total",
+			"Number of workgraph requests created.",
+			nil, nil,
+		),
+		resolvedTotal: prometheus.NewDesc(
+			"workgraph_resolved_total",
+			"Number of workgraph requests resolved, including those that failed.",
+			nil, nil,
+		),
+		selfDependencyTotal: prometheus.NewDesc(
+			"workgraph_self_dependencies_total",
+			"Number of workgraph requests that failed due to a self-dependency.",
+			nil, nil,
+		),
+		unresolvedTotal: prometheus.NewDesc(
+			"workgraph_unresolved_total",
+			"Number of workgraph requests that failed because their responsible worker was dropped.",
+			nil, nil,
+		),
+		inFlight: prometheus.NewDesc(
+			"workgraph_in_flight",
+			"Number of workgraph requests created but not yet resolved.",
+			nil, nil,
+		),
+	}
+}
+
+// Describe implements [prometheus.Collector].
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.requestsTotal
+	ch <- c.resolvedTotal
+	ch <- c.selfDependencyTotal
+	ch <- c.unresolvedTotal
+	ch <- c.inFlight
+}
+
+// Collect implements [prometheus.Collector].
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	stats := workgraph.Stats()
+	ch <- prometheus.MustNewConstMetric(c.requestsTotal, prometheus.CounterValue, float64(stats.RequestsTotal))
+	ch <- prometheus.MustNewConstMetric(c.resolvedTotal, prometheus.CounterValue, float64(stats.ResolvedTotal))
+	ch <- prometheus.MustNewConstMetric(c.selfDependencyTotal, prometheus.CounterValue, float64(stats.SelfDependencyTotal))
+	ch <- prometheus.MustNewConstMetric(c.unresolvedTotal, prometheus.CounterValue, float64(stats.UnresolvedTotal))
+	ch <- prometheus.MustNewConstMetric(c.inFlight, prometheus.GaugeValue, float64(stats.InFlight))
+}
@@ -0,0 +1,50 @@
+package workgraphmetrics_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/apparentlymart/go-workgraph/workgraph"
+	"github.com/apparentlymart/go-workgraph/workgraph/workgraphmetrics"
+)
+
+func TestCollector(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(workgraphmetrics.NewCollector()); err != nil {
+		t.Fatalf("failed to register collector: %s", err)
+	}
+
+	w := workgraph.NewWorker()
+	resolver, _ := workgraph.NewRequest[int](w)
+	resolver.ReportSuccess(w, 1)
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %s", err)
+	}
+	got := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			switch {
+			case metric.GetCounter() != nil:
+				got[family.GetName()] = metric.GetCounter().GetValue()
+			case metric.GetGauge() != nil:
+				got[family.GetName()] = metric.GetGauge().GetValue()
+			}
+		}
+	}
+
+	stats := workgraph.Stats()
+	want := map[string]float64{
+		"workgraph_requests_total":          float64(stats.RequestsTotal),
+		"workgraph_resolved_total":          float64(stats.ResolvedTotal),
+		"workgraph_self_dependencies_total": float64(stats.SelfDependencyTotal),
+		"workgraph_unresolved_total":        float64(stats.UnresolvedTotal),
+		"workgraph_in_flight":               float64(stats.InFlight),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("wrong metrics\n" + diff)
+	}
+}
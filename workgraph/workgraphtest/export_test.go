@@ -0,0 +1,16 @@
+package workgraphtest
+
+import (
+	"time"
+)
+
+// SetStuckWorkerGracePeriod replaces how long [AssertNoStuckWorkers] waits
+// before reporting stuck workers, so that tests which expect a report need
+// not wait for the full period. The returned function restores the original.
+func SetStuckWorkerGracePeriod(d time.Duration) (restore func()) {
+	prev := stuckWorkerGracePeriod
+	stuckWorkerGracePeriod = d
+	return func() {
+		stuckWorkerGracePeriod = prev
+	}
+}
@@ -0,0 +1,75 @@
+// Package workgraphtest contains helpers for testing code that uses package
+// workgraph.
+package workgraphtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apparentlymart/go-workgraph/workgraph"
+)
+
+// stuckWorkerGracePeriod is how long [AssertNoStuckWorkers] waits for
+// workers to stop awaiting before reporting them as stuck, to allow for
+// waiters whose requests were just resolved but whose goroutines have not
+// yet been scheduled to return from the await. It's a variable only so
+// that tests can shorten it.
+var stuckWorkerGracePeriod = time.Second
+
+// AssertNoStuckWorkers arranges for the given test to fail if any worker is
+// still awaiting a request once the test has completed, which suggests that
+// the test has leaked a goroutine that will never be unblocked.
+//
+// Call this at the start of a test. Workers that were already awaiting a
+// request at that point, such as those leaked by earlier tests, are not
+// reported. The check runs as a cleanup function registered with
+// t.Cleanup, and so runs after the test function returns.
+//
+// Tests that use this helper should not run in parallel with other tests
+// that use package workgraph, because the check considers all workers in
+// the program, not only those used by the test.
+func AssertNoStuckWorkers(t testing.TB) {
+	t.Helper()
+	alreadyWaiting := make(map[workgraph.WorkerID]workgraph.RequestID)
+	for _, ws := range workgraph.Snapshot().Workers {
+		if !ws.Awaiting.IsZero() {
+			alreadyWaiting[ws.ID] = ws.Awaiting
+		}
+	}
+
+	t.Cleanup(func() {
+		t.Helper()
+		deadline := time.Now().Add(stuckWorkerGracePeriod)
+		for {
+			stuck := stuckWorkers(alreadyWaiting)
+			if len(stuck) == 0 {
+				return
+			}
+			if time.Now().After(deadline) {
+				for _, ws := range stuck {
+					if ws.Name != "" {
+						t.Errorf("worker %s (%q) is still awaiting request %s", ws.ID, ws.Name, ws.Awaiting)
+					} else {
+						t.Errorf("worker %s is still awaiting request %s", ws.ID, ws.Awaiting)
+					}
+				}
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+}
+
+func stuckWorkers(ignore map[workgraph.WorkerID]workgraph.RequestID) []workgraph.WorkerSnapshot {
+	var ret []workgraph.WorkerSnapshot
+	for _, ws := range workgraph.Snapshot().Workers {
+		if ws.Awaiting.IsZero() {
+			continue
+		}
+		if ignore[ws.ID] == ws.Awaiting {
+			continue
+		}
+		ret = append(ret, ws)
+	}
+	return ret
+}
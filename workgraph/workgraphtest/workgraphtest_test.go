@@ -0,0 +1,74 @@
+package workgraphtest_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/apparentlymart/go-workgraph/workgraph"
+	"github.com/apparentlymart/go-workgraph/workgraph/workgraphtest"
+)
+
+func TestAssertNoStuckWorkers(t *testing.T) {
+	// The stuck waiter below is reported only once the grace period ends,
+	// so we shorten it to keep the test fast.
+	restore := workgraphtest.SetStuckWorkerGracePeriod(10 * time.Millisecond)
+	defer restore()
+
+	mainWorker := workgraph.NewWorker()
+	resolver, promise := workgraph.NewRequest[string](mainWorker)
+
+	fake := &fakeTB{TB: t}
+	workgraphtest.AssertNoStuckWorkers(fake)
+	blocked := make(chan struct{})
+	waiter := workgraph.NewNamedWorker("waiter")
+	waiter.SetOnBlock(func(workgraph.RequestID) {
+		close(blocked)
+	})
+	done := make(chan struct{})
+	go func() {
+		promise.Await(waiter)
+		close(done)
+	}()
+	<-blocked
+
+	fake.runCleanups()
+	if len(fake.errors) != 1 {
+		t.Fatalf("wrong number of errors %d; want 1\n%q", len(fake.errors), fake.errors)
+	}
+
+	// Once the waiter is unblocked there's nothing to report.
+	fake = &fakeTB{TB: t}
+	workgraphtest.AssertNoStuckWorkers(fake)
+	resolver.ReportSuccess(mainWorker, "ok")
+	<-done
+	fake.runCleanups()
+	if len(fake.errors) != 0 {
+		t.Fatalf("unexpected errors\n%q", fake.errors)
+	}
+}
+
+// fakeTB is a [testing.TB] that collects errors and cleanup functions
+// instead of passing them to the real test, so that we can test the
+// failure case.
+type fakeTB struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Errorf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func (tb *fakeTB) Cleanup(f func()) {
+	tb.cleanups = append(tb.cleanups, f)
+}
+
+func (tb *fakeTB) runCleanups() {
+	for i := len(tb.cleanups) - 1; i >= 0; i-- {
+		tb.cleanups[i]()
+	}
+}